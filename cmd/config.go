@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/chanzuckerberg/camelot/pkg/history"
+	"github.com/chanzuckerberg/camelot/pkg/printer"
+	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+	"github.com/chanzuckerberg/camelot/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+const (
+	flagConfig               = "config"
+	flagEndOfLifeURL         = "endoflife-url"
+	flagArtifactHubURL       = "artifacthub-url"
+	flagTerraformRegistryURL = "terraform-registry-url"
+	flagGithubAPIURL         = "github-api-url"
+	flagS3URL                = "s3-url"
+	flagProxy                = "proxy"
+	flagTagColumn            = "tag-column"
+	flagOwnerTag             = "owner-tag"
+	flagOwnersFile           = "owners-file"
+	flagGroupBy              = "group-by"
+	flagCollapseValid        = "collapse-valid"
+	flagUpgradePath          = "upgrade-path"
+	flagSortBy               = "sort-by"
+	flagSoonest              = "soonest"
+)
+
+var (
+	configFile      string
+	endpoints       util.Endpoints
+	proxy           string
+	tagColumns      []string
+	ownerTags       []string
+	ownersFile      string
+	ownerMapping    util.OwnerMapping
+	groupBy         string
+	collapseValid   bool
+	showUpgradePath bool
+	sortBy          []string
+	soonest         int
+)
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&configFile, flagConfig, "", "Camelot config file (env CAMELOT_CONFIG)")
+	rootCmd.PersistentFlags().StringVar(&endpoints.EndOfLife, flagEndOfLifeURL, "", fmt.Sprintf("endoflife.date API base url (env CAMELOT_ENDOFLIFE_URL). Defaults to %s.", util.DefaultEndpoints.EndOfLife))
+	rootCmd.PersistentFlags().StringVar(&endpoints.ArtifactHub, flagArtifactHubURL, "", fmt.Sprintf("Artifact Hub API base url (env CAMELOT_ARTIFACTHUB_URL). Defaults to %s.", util.DefaultEndpoints.ArtifactHub))
+	rootCmd.PersistentFlags().StringVar(&endpoints.TerraformRegistry, flagTerraformRegistryURL, "", fmt.Sprintf("Terraform registry API base url (env CAMELOT_TERRAFORM_REGISTRY_URL). Defaults to %s.", util.DefaultEndpoints.TerraformRegistry))
+	rootCmd.PersistentFlags().StringVar(&endpoints.GithubAPI, flagGithubAPIURL, "", fmt.Sprintf("Github API base url (env CAMELOT_GITHUB_API_URL). Defaults to %s.", util.DefaultEndpoints.GithubAPI))
+	rootCmd.PersistentFlags().StringVar(&endpoints.S3, flagS3URL, "", "S3-compatible storage url for s3:// outputs, e.g. a MinIO server (env CAMELOT_S3_URL). Defaults to AWS S3.")
+	rootCmd.PersistentFlags().StringVar(&proxy, flagProxy, "", "Proxy url for all outbound requests (env CAMELOT_PROXY). Defaults to HTTP_PROXY/HTTPS_PROXY.")
+	rootCmd.PersistentFlags().StringSliceVar(&tagColumns, flagTagColumn, []string{}, "Tag to show as a column in text output (env CAMELOT_TAG_COLUMNS). Multiple tags can be specified.")
+	rootCmd.PersistentFlags().StringSliceVar(&ownerTags, flagOwnerTag, []string{}, fmt.Sprintf("Tag naming the owning team, in order of precedence (env CAMELOT_OWNER_TAGS). Defaults to %s.", strings.Join(util.DefaultOwnerTags, ",")))
+	rootCmd.PersistentFlags().StringVar(&ownersFile, flagOwnersFile, "", "YAML file mapping resources to owners, for resources without an owner tag, CODEOWNERS entry or TFC workspace owner (env CAMELOT_OWNERS_FILE)")
+}
+
+// Settings are resolved in order of precedence: flags, environment variables, config file, defaults
+func loadConfig(cmd *cobra.Command) error {
+	config := &util.Config{}
+	if len(configFile) == 0 {
+		configFile = os.Getenv("CAMELOT_CONFIG")
+	}
+	if len(configFile) > 0 {
+		c, err := util.LoadConfig(configFile)
+		if err != nil {
+			return err
+		}
+		config = c
+	}
+
+	resolve := func(flag, env string, value *string) {
+		if cmd.Flags().Changed(flag) {
+			return
+		}
+		if v, ok := os.LookupEnv(env); ok {
+			*value = v
+		}
+	}
+	resolve(flagEndOfLifeURL, "CAMELOT_ENDOFLIFE_URL", &endpoints.EndOfLife)
+	resolve(flagArtifactHubURL, "CAMELOT_ARTIFACTHUB_URL", &endpoints.ArtifactHub)
+	resolve(flagTerraformRegistryURL, "CAMELOT_TERRAFORM_REGISTRY_URL", &endpoints.TerraformRegistry)
+	resolve(flagGithubAPIURL, "CAMELOT_GITHUB_API_URL", &endpoints.GithubAPI)
+	resolve(flagS3URL, "CAMELOT_S3_URL", &endpoints.S3)
+	resolve(flagProxy, "CAMELOT_PROXY", &proxy)
+
+	e := endpoints
+	if len(e.EndOfLife) == 0 {
+		e.EndOfLife = config.Endpoints.EndOfLife
+	}
+	if len(e.ArtifactHub) == 0 {
+		e.ArtifactHub = config.Endpoints.ArtifactHub
+	}
+	if len(e.TerraformRegistry) == 0 {
+		e.TerraformRegistry = config.Endpoints.TerraformRegistry
+	}
+	if len(e.GithubAPI) == 0 {
+		e.GithubAPI = config.Endpoints.GithubAPI
+	}
+	if len(e.S3) == 0 {
+		e.S3 = config.Endpoints.S3
+	}
+	util.SetEndpoints(e)
+
+	p := proxy
+	if len(p) == 0 {
+		p = config.Proxy
+	}
+	client, err := util.NewHTTPClient(p)
+	if err != nil {
+		return err
+	}
+	util.SetHTTPClient(client)
+
+	policy, err := util.NewPolicy(config.Policy)
+	if err != nil {
+		return fmt.Errorf("invalid config file %s: %w", configFile, err)
+	}
+	util.SetPolicy(policy)
+
+	resolve(flagHistoryDir, "CAMELOT_HISTORY_DIR", &historyDir)
+	if len(historyDir) == 0 {
+		historyDir = config.HistoryDir
+	}
+	if len(historyDir) == 0 {
+		historyDir = history.DefaultDir()
+	}
+
+	resolveList := func(flag, env string, value *[]string, configValue []string) {
+		if cmd.Flags().Changed(flag) {
+			return
+		}
+		if v, ok := os.LookupEnv(env); ok {
+			*value = strings.Split(v, ",")
+		} else {
+			*value = configValue
+		}
+	}
+	resolveList(flagTagColumn, "CAMELOT_TAG_COLUMNS", &tagColumns, config.TagColumns)
+	resolveList(flagOwnerTag, "CAMELOT_OWNER_TAGS", &ownerTags, config.Ownership.Tags)
+	util.SetOwnerTags(ownerTags)
+
+	resolve(flagOwnersFile, "CAMELOT_OWNERS_FILE", &ownersFile)
+	if len(ownersFile) == 0 {
+		ownersFile = config.Ownership.File
+	}
+	ownerMapping = nil
+	if len(ownersFile) > 0 {
+		ownerMapping, err = util.LoadOwnerMapping(ownersFile)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveOwners fills in the owners the scrapers could not tell from tags, the parents and the owners file
+func resolveOwners(report *types.InventoryReport) {
+	util.ResolveOwners(report, ownerMapping)
+}
+
+func printOpts() []printer.PrintOpt {
+	return []printer.PrintOpt{printer.WithTagColumns(tagColumns...), printer.WithGroupBy(groupBy), printer.WithCollapseValid(collapseValid), printer.WithUpgradePath(showUpgradePath), printer.WithSortBy(sortBy...), printer.WithSoonest(soonest)}
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/chanzuckerberg/camelot/pkg/printer"
+	"github.com/chanzuckerberg/camelot/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <before> <after>",
+	Short: "compares two saved reports",
+	Long: `Compares two reports saved with -o json and lists the resources which were added, removed,
+upgraded, downgraded or changed their status. Resources are matched by their kind, parents, id and arn.
+Use - to read a report from stdin.`,
+	Args: cobra.ExactArgs(2),
+	RunE: diff,
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+	diffCmd.Flags().StringVarP(&outputFormat, flagOutputFormat, "o", "text", "Output format (json, markdown, text). Defaults to text.")
+	diffCmd.Flags().StringArrayVarP(&filter, flagFilter, "f", []string{}, "Report filter, applied to both reports (e.g. -f kind=eks or -f 'kind = eks and version < 1.27'). Defaults to empty. Multiple filters can be specified.")
+}
+
+func diff(cmd *cobra.Command, args []string) error {
+	reports, err := util.LoadReports(args)
+	if err != nil {
+		return err
+	}
+	reportFilter, err := util.CreateFilter(filter)
+	if err != nil {
+		return err
+	}
+	before := util.FilterReport(reports[0], reportFilter)
+	after := util.FilterReport(reports[1], reportFilter)
+
+	err = printer.PrintDiff(util.DiffReports(before, after), outputFormat)
+	if err != nil {
+		return fmt.Errorf("failed to print diff: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chanzuckerberg/camelot/pkg/history"
+	"github.com/chanzuckerberg/camelot/pkg/printer"
+	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+	"github.com/chanzuckerberg/camelot/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+const (
+	flagHistoryDir = "history-dir"
+	flagRecord     = "record"
+	flagSince      = "since"
+	flagBy         = "by"
+	flagInterval   = "interval"
+)
+
+var (
+	historyCmd = &cobra.Command{
+		Use:   "history",
+		Short: "queries scrapes recorded with --record",
+		Long:  ``,
+	}
+	historyTrendCmd = &cobra.Command{
+		Use:   "trend",
+		Short: "shows resource counts by status over time",
+		Long: `Shows resource counts by status over time, grouped by kind, account, source, owner or a tag.
+Within each interval the latest recorded scrape of every account, org or source is counted.`,
+		Args: cobra.NoArgs,
+		RunE: historyTrend,
+	}
+	historyAgeCmd = &cobra.Command{
+		Use:   "age",
+		Short: "shows how long resources have been in WARNING or CRITICAL status",
+		Long:  ``,
+		Args:  cobra.NoArgs,
+		RunE:  historyAge,
+	}
+	historyDir string
+	record     bool
+	since      string
+	trendBy    string
+	interval   string
+)
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+	historyCmd.AddCommand(historyTrendCmd, historyAgeCmd)
+
+	historyCmd.PersistentFlags().StringVar(&historyDir, flagHistoryDir, "", historyDirUsage())
+	historyCmd.PersistentFlags().StringVar(&since, flagSince, "90d", "How far back to look, e.g. 30d, 12w or 1y. Defaults to 90d.")
+	historyCmd.PersistentFlags().StringVarP(&outputFormat, flagOutputFormat, "o", "text", "Output format (json, text). Defaults to text.")
+	historyTrendCmd.Flags().StringVar(&trendBy, flagBy, "kind", fmt.Sprintf("Group resources by %s. Defaults to kind.", strings.Join(history.GroupKeys(), ", ")))
+	historyTrendCmd.Flags().StringVar(&interval, flagInterval, "day", "Interval of the trend: day, week or month. Defaults to day.")
+	historyAgeCmd.Flags().StringArrayVarP(&filter, flagFilter, "f", []string{}, "Resource filter (e.g. -f kind=eks or -f 'status = critical and kind != helm'). Defaults to empty. Multiple filters can be specified.")
+
+	scrapeCmd.PersistentFlags().StringVar(&historyDir, flagHistoryDir, "", historyDirUsage())
+	scrapeCmd.PersistentFlags().BoolVar(&record, flagRecord, false, "Record the scraped inventory in the history store")
+}
+
+func historyDirUsage() string {
+	return fmt.Sprintf("Directory of the history store (env CAMELOT_HISTORY_DIR). Defaults to %s.", history.DefaultDir())
+}
+
+// recordReport stores the unfiltered report when --record is set. The scope tells apart scrapes of the
+// same source, like aws accounts.
+func recordReport(source, scope string, report *types.InventoryReport) error {
+	if !record || report == nil {
+		return nil
+	}
+	err := history.NewStore(historyDir).Record(history.Snapshot{
+		RecordedAt: time.Now(),
+		Source:     source,
+		Scope:      scope,
+		Report:     report,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record the inventory: %w", err)
+	}
+	return nil
+}
+
+func loadSnapshots() ([]history.Snapshot, error) {
+	days, err := util.ParseDays(since)
+	if err != nil || days < 0 {
+		return nil, fmt.Errorf("invalid --%s %q", flagSince, since)
+	}
+	return history.NewStore(historyDir).Snapshots(time.Now().AddDate(0, 0, -int(days)))
+}
+
+func historyTrend(cmd *cobra.Command, args []string) error {
+	snapshots, err := loadSnapshots()
+	if err != nil {
+		return err
+	}
+	trend, err := history.Trend(snapshots, trendBy, interval)
+	if err != nil {
+		return err
+	}
+	return printer.PrintTrend(trend, outputFormat)
+}
+
+func historyAge(cmd *cobra.Command, args []string) error {
+	snapshots, err := loadSnapshots()
+	if err != nil {
+		return err
+	}
+	reportFilter, err := util.CreateFilter(filter)
+	if err != nil {
+		return err
+	}
+	ages := []history.ResourceAge{}
+	for _, age := range history.Ages(snapshots, time.Now()) {
+		if util.IsMatch(age.Resource, reportFilter) {
+			ages = append(ages, age)
+		}
+	}
+	return printer.PrintAges(ages, outputFormat)
+}
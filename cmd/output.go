@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/chanzuckerberg/camelot/pkg/printer"
+	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+	"github.com/chanzuckerberg/camelot/pkg/sink"
+	"github.com/chanzuckerberg/camelot/pkg/util"
+)
+
+const flagOutputFile = "output-file"
+
+const outputFileUsage = "Also write the report to a file, [FORMAT=]DESTINATION, where DESTINATION is a path, - for stdout or s3://<bucket>/<prefix> " +
+	"for a timestamped upload. FORMAT defaults to the one of the file extension, or else to -o. Multiple outputs can be specified."
+
+var outputFiles []string
+
+// output is a report written with --output-file, besides the -o output on stdout
+type output struct {
+	format string
+	sink   sink.Sink
+}
+
+// parseOutputs opens the sinks of --output-file before anything is scraped, so that a mistake fails fast
+func parseOutputs(ctx context.Context) ([]output, error) {
+	outputs := []output{}
+	for _, spec := range outputFiles {
+		format, destination := splitOutputSpec(spec)
+		if len(destination) == 0 {
+			return nil, fmt.Errorf("invalid --%s %q: no destination", flagOutputFile, spec)
+		}
+		if len(format) == 0 {
+			f, ok := printer.FormatOfFile(destination)
+			if !ok {
+				f = outputFormat
+			}
+			format = f
+		}
+
+		extension, contentType := printer.FileType(format)
+		s, err := sink.New(ctx, destination, sink.File{Extension: extension, ContentType: contentType}, sink.WithEndpoint(util.GetEndpoints().S3))
+		if err != nil {
+			return nil, fmt.Errorf("invalid --%s %q: %w", flagOutputFile, spec, err)
+		}
+		outputs = append(outputs, output{format: format, sink: s})
+	}
+	return outputs, nil
+}
+
+// splitOutputSpec splits [FORMAT=]DESTINATION. Destinations may have a = of their own, like
+// s3://archive/dt=2024-06-01/, so the spec only names a format when it starts with one. Custom columns
+// and templates have a = of their own, their destination follows the last one.
+func splitOutputSpec(spec string) (string, string) {
+	for _, prefix := range []string{"custom-columns=", "go-template="} {
+		if rest, ok := strings.CutPrefix(spec, prefix); ok {
+			i := strings.LastIndex(rest, "=")
+			if i < 0 {
+				return spec, ""
+			}
+			return prefix + rest[:i], rest[i+1:]
+		}
+	}
+	if format, destination, ok := strings.Cut(spec, "="); ok && printer.IsFormat(format) {
+		return format, destination
+	}
+	return "", spec
+}
+
+// writeOutputs writes the reports of a command, combined into one, to every output. A failing output
+// does not keep the others from being written.
+func writeOutputs(ctx context.Context, outputs []output, reports []*types.InventoryReport, filter util.ReportFilter) error {
+	if len(outputs) == 0 || len(reports) == 0 {
+		return nil
+	}
+	report := combineReports(reports)
+
+	errs := []error{}
+	for _, o := range outputs {
+		var b bytes.Buffer
+		err := printer.WriteReport(&b, report, filter, o.format, printOpts()...)
+		if err == nil {
+			err = o.sink.Write(ctx, b.Bytes())
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to write the %s report to %s: %w", o.format, o.sink, err))
+		}
+	}
+	return errors.Join(errs...)
+}
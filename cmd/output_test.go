@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitOutputSpec(t *testing.T) {
+	r := require.New(t)
+
+	tests := []struct {
+		spec, format, destination string
+	}{
+		{"report.json", "", "report.json"},
+		{"-", "", "-"},
+		{"markdown=summary.md", "markdown", "summary.md"},
+		{"json=s3://archive/dt=2024-06-01/", "json", "s3://archive/dt=2024-06-01/"},
+		{"s3://archive/dt=2024-06-01/aws.json", "", "s3://archive/dt=2024-06-01/aws.json"},
+		{"reports/a=b.csv", "", "reports/a=b.csv"},
+		{"custom-columns=ID:.ID,VERSION:.Version=ids.txt", "custom-columns=ID:.ID,VERSION:.Version", "ids.txt"},
+		{"go-template={{.Identity}}=-", "go-template={{.Identity}}", "-"},
+		{"custom-columns=ID:.ID", "custom-columns=ID:.ID", ""},
+		{"summary=", "summary", ""},
+	}
+	for _, test := range tests {
+		format, destination := splitOutputSpec(test.spec)
+		r.Equal(test.format, format, test.spec)
+		r.Equal(test.destination, destination, test.spec)
+	}
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/chanzuckerberg/camelot/pkg/printer"
+	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+	"github.com/chanzuckerberg/camelot/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+var (
+	reportCmd = &cobra.Command{
+		Use:   "report",
+		Short: "works with inventory reports saved with -o json",
+		Long:  ``,
+	}
+	reportShowCmd = &cobra.Command{
+		Use:   "show <files...>",
+		Short: "filters and prints saved reports, one at a time",
+		Long:  `Filters and prints saved reports, one at a time. Use - to read a report from stdin.`,
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  reportShow,
+	}
+	reportMergeCmd = &cobra.Command{
+		Use:   "merge <files...>",
+		Short: "merges saved reports into one, then filters and prints it",
+		Long:  `Merges saved reports into one, then filters and prints it. Use - to read a report from stdin.`,
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  reportMerge,
+	}
+	reportSchemaCmd = &cobra.Command{
+		Use:   "schema",
+		Short: "prints the JSON schema of saved reports",
+		Long:  ``,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			_, err := os.Stdout.Write(types.ReportSchema)
+			return err
+		},
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+	reportCmd.AddCommand(reportShowCmd, reportMergeCmd, reportSchemaCmd)
+	reportCmd.PersistentFlags().StringVarP(&outputFormat, flagOutputFormat, "o", "text", "Output format (json, yaml, text, markdown, csv, tsv, html, sarif, junit, cyclonedx, prometheus, summary, dot, mermaid, graph-json, custom-columns=<HEADER>:<FIELD>,... or go-template=<TEMPLATE>). Defaults to text.")
+	reportCmd.PersistentFlags().StringArrayVar(&outputFiles, flagOutputFile, []string{}, outputFileUsage)
+	reportCmd.PersistentFlags().StringArrayVarP(&filter, flagFilter, "f", []string{}, "Report filter: a key=value pair or an expression (e.g. -f kind=eks or -f 'kind in (eks,rds) and eol.remaining_days < 90'). Defaults to empty. Multiple filters can be specified.")
+	reportCmd.PersistentFlags().StringVar(&groupBy, flagGroupBy, "", "Group the report by a field (owner, kind, parent, account, status or tag.<key>), with status subtotals per group")
+	reportCmd.PersistentFlags().StringSliceVar(&sortBy, flagSortBy, []string{}, "Sort resources by fields (e.g. eol.remaining_days, kind, status or version), prefixed with - for descending order")
+	reportCmd.PersistentFlags().BoolVar(&collapseValid, flagCollapseValid, false, "Collapse the subtrees of dot, mermaid and graph-json output in which every resource is VALID")
+	reportCmd.PersistentFlags().StringVar(&failOn, flagFailOn, "", "Exit with a non-zero code when any filtered resource is at least as severe as this status (warning or critical)")
+	reportCmd.PersistentFlags().IntVar(&soonest, flagSoonest, util.DefaultSoonest, "Number of resources past or closest to their end of life listed by -o summary")
+	reportCmd.PersistentFlags().BoolVar(&showUpgradePath, flagUpgradePath, false, "Add a text column with the recommended upgrade path (remediation) of EKS clusters, RDS clusters and Lambda runtimes")
+}
+
+func reportShow(cmd *cobra.Command, args []string) error {
+	reportFilter, err := util.CreateFilter(filter)
+	if err != nil {
+		return err
+	}
+	failing, err := newFailOnCheck(reportFilter)
+	if err != nil {
+		return err
+	}
+	outputs, err := parseOutputs(cmd.Context())
+	if err != nil {
+		return err
+	}
+	reports, err := util.LoadReports(args)
+	if err != nil {
+		return err
+	}
+	for i, report := range reports {
+		resolveOwners(report)
+		err = printer.PrintReport(report, reportFilter, outputFormat, printOpts()...)
+		if err != nil {
+			return fmt.Errorf("failed to print report %s: %w", args[i], err)
+		}
+		failing.add(report)
+	}
+	return errors.Join(failing.err(), writeOutputs(cmd.Context(), outputs, reports, reportFilter))
+}
+
+func reportMerge(cmd *cobra.Command, args []string) error {
+	reportFilter, err := util.CreateFilter(filter)
+	if err != nil {
+		return err
+	}
+	failing, err := newFailOnCheck(reportFilter)
+	if err != nil {
+		return err
+	}
+	outputs, err := parseOutputs(cmd.Context())
+	if err != nil {
+		return err
+	}
+	reports, err := util.LoadReports(args)
+	if err != nil {
+		return err
+	}
+	merged := util.CombineReports(reports)
+
+	merged.Identity = util.CommonIdentity(reports)
+
+	// Merging AWS and TFC reports links AWS resources to the workspaces managing them
+	if hasTfcResources(&merged) {
+		util.Correlate(&merged, &merged)
+	}
+	resolveOwners(&merged)
+	err = printer.PrintReport(&merged, reportFilter, outputFormat, printOpts()...)
+	if err != nil {
+		return fmt.Errorf("failed to print report: %w", err)
+	}
+	failing.add(&merged)
+	return errors.Join(failing.err(), writeOutputs(cmd.Context(), outputs, []*types.InventoryReport{&merged}, reportFilter))
+}
+
+// combineReports merges the reports of a command, like those of the accounts of scrape aws --all,
+// into one
+func combineReports(reports []*types.InventoryReport) *types.InventoryReport {
+	if len(reports) == 1 {
+		return reports[0]
+	}
+	combined := util.CombineReports(reports)
+	combined.Identity = util.CommonIdentity(reports)
+	return &combined
+}
+
+func hasTfcResources(report *types.InventoryReport) bool {
+	for _, item := range report.Resources {
+		if item.GetVersionedResource().Kind == types.KindTFCResource {
+			return true
+		}
+	}
+	return false
+}
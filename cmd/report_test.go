@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/chanzuckerberg/camelot/pkg/printer"
+	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+	"github.com/chanzuckerberg/camelot/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func accountReport(account string, statuses ...types.Status) *types.InventoryReport {
+	report := &types.InventoryReport{Identity: types.Indentity{AwsAccountNumber: account}}
+	for i, status := range statuses {
+		report.Resources = append(report.Resources, types.EKSCluster{VersionedResource: types.VersionedResource{
+			Kind:    types.KindEKSCluster,
+			ID:      account + "-" + string(rune('a'+i)),
+			Parents: []types.ParentResource{{Kind: types.KindAWSAccount, ID: account}},
+			Version: "1.27",
+			EOL:     types.EOLStatus{EOLDate: "2099-01-01", Status: status},
+		}})
+	}
+	return report
+}
+
+func TestCombineReports(t *testing.T) {
+	r := require.New(t)
+
+	single := accountReport("111", types.StatusValid)
+	r.Same(single, combineReports([]*types.InventoryReport{single}))
+
+	reports := []*types.InventoryReport{
+		accountReport("111", types.StatusCritical, types.StatusValid),
+		accountReport("222", types.StatusWarning),
+		{Errors: []types.ScrapeError{{Source: "aws", Message: "failed to load config for profile sandbox: no such profile"}}},
+	}
+	combined := combineReports(reports)
+	r.Len(combined.Resources, 3)
+	r.Len(combined.Errors, 1)
+	r.Empty(combined.Identity.AwsAccountNumber)
+
+	// scrape aws --all -o summary prints one summary of every account
+	filter, err := util.CreateFilter(nil)
+	r.NoError(err)
+	var b bytes.Buffer
+	r.NoError(printer.WriteReport(&b, combined, filter, "summary"))
+	summary := b.String()
+	r.NotContains(summary, "Account:")
+	r.Contains(summary, "3 resources")
+	r.Contains(summary, "1 scrape error, the report is incomplete")
+	r.Contains(summary, "aws:111  1         0        1      2")
+	r.Contains(summary, "aws:222  0         1        0      1")
+	r.Equal(1, bytes.Count(b.Bytes(), []byte("By kind:")))
+}
@@ -8,13 +8,17 @@ import (
 var (
 	verbose bool
 	rootCmd = &cobra.Command{
-		Use:   "camelot",
-		Short: "camelot - an end of life inventory tool for AWS",
-		Long:  ``,
-		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		Use:           "camelot",
+		Short:         "camelot - an end of life inventory tool for AWS",
+		Long:          ``,
+		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			// Arguments are valid at this point, errors are no longer usage errors
+			cmd.SilenceUsage = true
 			if verbose {
 				logrus.SetLevel(logrus.DebugLevel)
 			}
+			return loadConfig(cmd)
 		},
 	}
 )
@@ -1,17 +1,23 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 
 	"github.com/chanzuckerberg/camelot/pkg/printer"
 	scraper "github.com/chanzuckerberg/camelot/pkg/scraper/aws"
+	"github.com/chanzuckerberg/camelot/pkg/scraper/tfc"
+	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
 	"github.com/chanzuckerberg/camelot/pkg/util"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
 const (
-	flagAll = "all"
+	flagAll           = "all"
+	flagLifecycleFile = "lifecycle-file"
+	flagTfc           = "tfc"
 )
 
 var (
@@ -21,30 +27,134 @@ var (
 		Long:  ``,
 		RunE:  scrape,
 	}
-	scanAll bool
+	scanAll       bool
+	lifecycleFile string
+	correlateTfc  bool
 )
 
 func init() {
 	scrapeCmd.AddCommand(scrapeAwsCmd)
 	scrapeAwsCmd.Flags().BoolVarP(&scanAll, flagAll, "a", false, "Scan all aws profiles")
+	scrapeAwsCmd.Flags().StringVar(&lifecycleFile, flagLifecycleFile, "", "YAML file with custom product lifecycle definitions, merged with and overriding endoflife.date data")
+	scrapeAwsCmd.Flags().BoolVar(&correlateTfc, flagTfc, false, "Also scrape TFC/TFE and link resources to the workspaces managing them, flagging the others as unmanaged")
 }
 
 func scrape(cmd *cobra.Command, args []string) error {
+	var recordErr error
+
+	reportFilter, err := util.CreateFilter(filter)
+	if err != nil {
+		return err
+	}
+	failing, err := newFailOnCheck(reportFilter)
+	if err != nil {
+		return err
+	}
+	outputs, err := parseOutputs(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	if len(lifecycleFile) > 0 {
+		err = scraper.LoadLifecycleFile(lifecycleFile)
+		if err != nil {
+			return fmt.Errorf("failed to load lifecycle definitions: %w", err)
+		}
+	}
+
+	complete := true
+	var tfcReport *types.InventoryReport
+	var tfcErrors []types.ScrapeError
+	if correlateTfc {
+		tfcReport, err = tfc.Scrape(cmd.Context())
+		if err != nil {
+			logrus.Errorf("failed to scrape TFC, resources will not be correlated: %s", err.Error())
+			tfcErrors = append(tfcErrors, types.ScrapeError{
+				Source:  "tfc",
+				Message: fmt.Sprintf("failed to scrape TFC, resources were not correlated: %s", err.Error()),
+			})
+		} else if !tfcReport.Complete() {
+			logrus.Warn("some TFC workspace states could not be read, resources will not be flagged as unmanaged")
+		}
+	}
+
+	// A summary rolls all the accounts up, it is printed once they are scraped
+	printAccounts := outputFormat != "summary"
+	reports := []*types.InventoryReport{}
+	profileErrors, err := scrapeAWS(cmd.Context(), tfcReport, func(accountNumber string, report *types.InventoryReport) {
+		err := recordReport("aws", accountNumber, report)
+		if err != nil {
+			logrus.Error(err.Error())
+			recordErr = err
+		}
+
+		if printAccounts {
+			err = printer.PrintReport(report, reportFilter, outputFormat, printOpts()...)
+			if err != nil {
+				logrus.Errorf("failed to print report for account %s: %s", accountNumber, err.Error())
+			}
+		}
+		failing.add(report)
+		reports = append(reports, report)
+	})
+	if err != nil {
+		return err
+	}
+	// Profiles which could not be loaded have no account, their errors are reported on their own, like
+	// a failed TFC scrape
+	if sourceErrors := append(tfcErrors, profileErrors...); len(sourceErrors) > 0 {
+		report := &types.InventoryReport{Errors: sourceErrors}
+		if printAccounts {
+			err = printer.PrintReport(report, reportFilter, outputFormat, printOpts()...)
+			if err != nil {
+				logrus.Errorf("failed to print the scrape errors: %s", err.Error())
+			}
+		}
+		reports = append(reports, report)
+	}
+	if !printAccounts && len(reports) > 0 {
+		err = printer.PrintReport(combineReports(reports), reportFilter, outputFormat, printOpts()...)
+		if err != nil {
+			logrus.Errorf("failed to print the summary: %s", err.Error())
+		}
+	}
+	for _, report := range reports {
+		complete = complete && report.Complete()
+	}
+
+	logrus.Debug("Scraping complete")
+	// Outputs have the accounts in a single report
+	outputErr := writeOutputs(cmd.Context(), outputs, reports, reportFilter)
+	if !complete {
+		return errors.Join(failing.err(), outputErr, errIncompleteReport)
+	}
+	return errors.Join(failing.err(), recordErr, outputErr)
+}
+
+// scrapeAWS scrapes every account of the AWS profiles (all of them with --all) once, links resources
+// to the TFC workspaces of tfcReport when it is set, resolves owners and hands each account report to
+// handle. The profiles which could not be loaded are returned as scrape errors.
+func scrapeAWS(ctx context.Context, tfcReport *types.InventoryReport, handle func(accountNumber string, report *types.InventoryReport)) ([]types.ScrapeError, error) {
 	var err error
+	profileErrors := []types.ScrapeError{}
 	profiles := []string{""}
 	accountMap := map[string]bool{}
 
 	if scanAll {
 		profiles, err = scraper.GetAWSProfiles()
 		if err != nil {
-			return fmt.Errorf("failed to get AWS profiles: %w", err)
+			return nil, fmt.Errorf("failed to get AWS profiles: %w", err)
 		}
 	}
 
 	for _, profile := range profiles {
-		awsClient, err := scraper.NewAWSClient(cmd.Context(), scraper.WithProfile(profile))
+		awsClient, err := scraper.NewAWSClient(ctx, scraper.WithProfile(profile))
 		if err != nil {
 			logrus.Errorf("failed to load config for profile %s: %s", profile, err.Error())
+			profileErrors = append(profileErrors, types.ScrapeError{
+				Source:  "aws",
+				Message: fmt.Sprintf("failed to load config for profile %s: %s", profile, err.Error()),
+			})
 			continue
 		}
 		accountNumber := awsClient.GetAccountId()
@@ -54,17 +164,20 @@ func scrape(cmd *cobra.Command, args []string) error {
 		}
 		accountMap[accountNumber] = true
 
-		report, err := scraper.Scrape(cmd.Context(), scraper.WithProfile(profile))
+		report, err := scraper.Scrape(ctx, scraper.WithProfile(profile))
 		if err != nil {
 			logrus.Errorf("failed to scrape resources for profile %s: %s", profile, err.Error())
+			report = &types.InventoryReport{
+				Identity: types.Indentity{AwsAccountNumber: accountNumber},
+				Errors:   []types.ScrapeError{{Source: "aws", Account: accountNumber, Message: err.Error()}},
+			}
 		}
-
-		err = printer.PrintReport(report, util.CreateFilter(filter), outputFormat)
-		if err != nil {
-			logrus.Errorf("failed to print report for profile %s: %s", profile, err.Error())
+		if tfcReport != nil {
+			managed, unmanaged := util.Correlate(report, tfcReport)
+			logrus.Debugf("account %s: %d resources managed by TFC, %d unmanaged", accountNumber, managed, unmanaged)
 		}
+		resolveOwners(report)
+		handle(accountNumber, report)
 	}
-
-	logrus.Debug("Scraping complete")
-	return nil
+	return profileErrors, nil
 }
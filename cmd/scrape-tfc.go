@@ -1,12 +1,13 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/chanzuckerberg/camelot/pkg/printer"
 	scraper "github.com/chanzuckerberg/camelot/pkg/scraper/tfc"
+	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
 	"github.com/chanzuckerberg/camelot/pkg/util"
-	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
@@ -25,6 +26,18 @@ func init() {
 }
 
 func scrapeTfc(cmd *cobra.Command, args []string) error {
+	reportFilter, err := util.CreateFilter(filter)
+	if err != nil {
+		return err
+	}
+	failing, err := newFailOnCheck(reportFilter)
+	if err != nil {
+		return err
+	}
+	outputs, err := parseOutputs(cmd.Context())
+	if err != nil {
+		return err
+	}
 	report, err := scraper.Scrape(cmd.Context())
 	if err != nil {
 		return fmt.Errorf("failed to scrape resources: %w", err)
@@ -33,11 +46,22 @@ func scrapeTfc(cmd *cobra.Command, args []string) error {
 		return errors.New("No report was produced")
 	}
 	logrus.Debug("Scraping complete")
+	resolveOwners(report)
 
-	err = printer.PrintReport(report, util.CreateFilter(filter), outputFormat)
+	err = recordReport("tfc", "", report)
+	if err != nil {
+		return err
+	}
+
+	err = printer.PrintReport(report, reportFilter, outputFormat, printOpts()...)
 	if err != nil {
 		return fmt.Errorf("failed to print report: %w", err)
 	}
+	failing.add(report)
+	outputErr := writeOutputs(cmd.Context(), outputs, []*types.InventoryReport{report}, reportFilter)
 
-	return nil
+	if !report.Complete() {
+		return errors.Join(failing.err(), outputErr, errIncompleteReport)
+	}
+	return errors.Join(failing.err(), outputErr)
 }
@@ -1,6 +1,11 @@
 package cmd
 
 import (
+	"errors"
+	"fmt"
+
+	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+	"github.com/chanzuckerberg/camelot/pkg/util"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
@@ -8,6 +13,7 @@ import (
 const (
 	flagOutputFormat = "output"
 	flagFilter       = "filter"
+	flagFailOn       = "fail-on"
 )
 
 var (
@@ -22,10 +28,49 @@ var (
 	}
 	outputFormat string
 	filter       []string
+	failOn       string
 )
 
+var errIncompleteReport = errors.New("the inventory is incomplete, see the errors section of the report")
+
 func init() {
 	rootCmd.AddCommand(scrapeCmd)
-	scrapeCmd.PersistentFlags().StringVarP(&outputFormat, flagOutputFormat, "o", "text", "Output format (json, yaml, text). Defaults to text.")
-	scrapeCmd.PersistentFlags().StringSliceVarP(&filter, flagFilter, "f", []string{}, "Report filter (e.g. -f kind=eks or -f parent.kind=eks). Defaults to empty. Multiple filters can be specified.")
+	scrapeCmd.PersistentFlags().StringVarP(&outputFormat, flagOutputFormat, "o", "text", "Output format (json, yaml, text, markdown, csv, tsv, html, sarif, junit, cyclonedx, prometheus, summary, dot, mermaid, graph-json, custom-columns=<HEADER>:<FIELD>,... or go-template=<TEMPLATE>). Defaults to text.")
+	scrapeCmd.PersistentFlags().StringArrayVar(&outputFiles, flagOutputFile, []string{}, outputFileUsage)
+	scrapeCmd.PersistentFlags().StringArrayVarP(&filter, flagFilter, "f", []string{}, "Report filter: a key=value pair or an expression (e.g. -f kind=eks or -f 'kind in (eks,rds) and eol.remaining_days < 90'). Defaults to empty. Multiple filters can be specified.")
+	scrapeCmd.PersistentFlags().StringVar(&groupBy, flagGroupBy, "", "Group the report by a field (owner, kind, parent, account, status or tag.<key>), with status subtotals per group")
+	scrapeCmd.PersistentFlags().StringSliceVar(&sortBy, flagSortBy, []string{}, "Sort resources by fields (e.g. eol.remaining_days, kind, status or version), prefixed with - for descending order")
+	scrapeCmd.PersistentFlags().BoolVar(&collapseValid, flagCollapseValid, false, "Collapse the subtrees of dot, mermaid and graph-json output in which every resource is VALID")
+	scrapeCmd.PersistentFlags().StringVar(&failOn, flagFailOn, "", "Exit with a non-zero code when any filtered resource is at least as severe as this status (warning or critical)")
+	scrapeCmd.PersistentFlags().IntVar(&soonest, flagSoonest, util.DefaultSoonest, "Number of resources past or closest to their end of life listed by -o summary")
+	scrapeCmd.PersistentFlags().BoolVar(&showUpgradePath, flagUpgradePath, false, "Add a text column with the recommended upgrade path (remediation) of EKS clusters, RDS clusters and Lambda runtimes")
+}
+
+// failOnCheck counts the filtered resources of the reports of a command which reach the --fail-on status
+type failOnCheck struct {
+	threshold types.Status
+	filter    util.ReportFilter
+	failing   int
+}
+
+func newFailOnCheck(filter util.ReportFilter) (*failOnCheck, error) {
+	threshold, err := util.ParseStatusThreshold(failOn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --%s: %w", flagFailOn, err)
+	}
+	return &failOnCheck{threshold: threshold, filter: filter}, nil
+}
+
+func (c *failOnCheck) add(report *types.InventoryReport) {
+	filtered := util.FilterReport(report, c.filter)
+	if filtered != nil {
+		c.failing += util.CountAtOrAbove(*filtered, c.threshold)
+	}
+}
+
+func (c *failOnCheck) err() error {
+	if c.failing == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d resources are %s or worse (--%s %s)", c.failing, c.threshold, flagFailOn, failOn)
 }
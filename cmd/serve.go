@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"syscall"
+	"time"
+
+	scraper "github.com/chanzuckerberg/camelot/pkg/scraper/aws"
+	githubScraper "github.com/chanzuckerberg/camelot/pkg/scraper/github"
+	"github.com/chanzuckerberg/camelot/pkg/scraper/tfc"
+	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+	"github.com/chanzuckerberg/camelot/pkg/server"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+const (
+	flagListen    = "listen"
+	flagSource    = "source"
+	flagCacheFile = "cache-file"
+)
+
+var (
+	serveCmd = &cobra.Command{
+		Use:   "serve",
+		Short: "scrapes on a schedule and serves the inventory over HTTP",
+		Long: `Scrapes the sources on a schedule and serves the latest inventory:
+  /metrics            Prometheus metrics, like -o prometheus
+  /api/v1/resources   the report as JSON, filtered with ?filter=<FILTER> (the syntax of -f, repeatable)
+  /api/v1/summary     status counts by kind and scope, and the resources past or closest to their end of life (?limit=<N>)
+  /healthz            healthy once an inventory was scraped`,
+		Args: cobra.NoArgs,
+		RunE: serve,
+	}
+	listenAddress string
+	scrapeEvery   time.Duration
+	sources       []string
+	cacheFile     string
+)
+
+var supportedSources = []string{"aws", "github", "tfc"}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+	serveCmd.Flags().StringVar(&listenAddress, flagListen, ":8080", "Address to listen on")
+	serveCmd.Flags().DurationVar(&scrapeEvery, flagInterval, server.DefaultInterval, "Time between two scrapes")
+	serveCmd.Flags().StringSliceVar(&sources, flagSource, []string{"aws"}, "Sources to scrape (aws, github, tfc). With tfc, AWS resources are linked to the workspaces managing them.")
+	serveCmd.Flags().StringVar(&cacheFile, flagCacheFile, "", "File keeping the latest inventory, served right away after a restart. Defaults to memory only.")
+	serveCmd.Flags().BoolVarP(&scanAll, flagAll, "a", false, "Scan all aws profiles")
+	serveCmd.Flags().StringVar(&lifecycleFile, flagLifecycleFile, "", "YAML file with custom product lifecycle definitions, merged with and overriding endoflife.date data")
+	serveCmd.Flags().StringVar(&githubOrg, flagGithubOrg, "chanzuckerberg", "Github org to scan. Defaults to chanzuckerberg.")
+	serveCmd.Flags().StringVar(&historyDir, flagHistoryDir, "", historyDirUsage())
+	serveCmd.Flags().BoolVar(&record, flagRecord, false, "Record each scraped inventory in the history store")
+}
+
+func serve(cmd *cobra.Command, args []string) error {
+	for _, source := range sources {
+		if !slices.Contains(supportedSources, source) {
+			return fmt.Errorf("unsupported source %q, supported sources are: aws, github, tfc", source)
+		}
+	}
+	if scrapeEvery <= 0 {
+		return fmt.Errorf("invalid --%s %s", flagInterval, scrapeEvery)
+	}
+	if len(lifecycleFile) > 0 {
+		err := scraper.LoadLifecycleFile(lifecycleFile)
+		if err != nil {
+			return fmt.Errorf("failed to load lifecycle definitions: %w", err)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	s := server.NewServer(scrapeSources, server.WithInterval(scrapeEvery), server.WithCacheFile(cacheFile))
+	httpServer := &http.Server{Addr: listenAddress, Handler: s.Handler(), ReadHeaderTimeout: 10 * time.Second}
+	go s.Run(ctx)
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = httpServer.Shutdown(shutdownCtx)
+	}()
+
+	logrus.Infof("serving the inventory of %v on %s, scraped every %s", sources, listenAddress, scrapeEvery)
+	err := httpServer.ListenAndServe()
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("failed to serve: %w", err)
+	}
+	return nil
+}
+
+// scrapeSources scrapes every source into a single report, as scrape tfc, scrape aws --tfc and scrape
+// github would. Sources which fail are reported as errors of the report, unless they all fail, in which
+// case the error is returned so that the previous inventory keeps being served.
+func scrapeSources(ctx context.Context) (*types.InventoryReport, error) {
+	// The latest end of life data, chart and provider versions are fetched again on every refresh
+	scraper.ResetCaches()
+	githubScraper.ResetCaches()
+
+	reports := []*types.InventoryReport{}
+	failed := []error{}
+	sourceError := func(source string, err error) *types.InventoryReport {
+		logrus.Errorf("failed to scrape %s: %s", source, err.Error())
+		failed = append(failed, fmt.Errorf("failed to scrape %s: %w", source, err))
+		return &types.InventoryReport{Errors: []types.ScrapeError{{Source: source, Message: err.Error()}}}
+	}
+	add := func(source, scope string, report *types.InventoryReport) {
+		err := recordReport(source, scope, report)
+		if err != nil {
+			logrus.Error(err.Error())
+		}
+		reports = append(reports, report)
+	}
+
+	// TFC goes first, to link AWS resources to workspaces
+	var tfcReport *types.InventoryReport
+	if slices.Contains(sources, "tfc") {
+		report, err := tfc.Scrape(ctx)
+		if err != nil {
+			reports = append(reports, sourceError("tfc", err))
+		} else {
+			tfcReport = report
+			resolveOwners(report)
+			add("tfc", "", report)
+		}
+	}
+	if slices.Contains(sources, "aws") {
+		profileErrors, err := scrapeAWS(ctx, tfcReport, func(accountNumber string, report *types.InventoryReport) {
+			add("aws", accountNumber, report)
+		})
+		if err != nil {
+			reports = append(reports, sourceError("aws", err))
+		} else if len(profileErrors) > 0 {
+			reports = append(reports, &types.InventoryReport{Errors: profileErrors})
+		}
+	}
+	if slices.Contains(sources, "github") {
+		report, err := githubScraper.Scrape(ctx, githubOrg)
+		if err != nil {
+			reports = append(reports, sourceError("github", err))
+		} else {
+			resolveOwners(report)
+			add("github", githubOrg, report)
+		}
+	}
+
+	if len(failed) == len(sources) {
+		return nil, errors.Join(failed...)
+	}
+	return combineReports(reports), nil
+}
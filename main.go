@@ -1,6 +1,8 @@
 package main
 
 import (
+	"os"
+
 	"github.com/chanzuckerberg/camelot/cmd"
 	"github.com/sirupsen/logrus"
 )
@@ -8,7 +10,10 @@ import (
 func main() {
 	logrus.SetLevel(logrus.InfoLevel)
 	err := cmd.Execute()
-	if err != nil && err.Error() != "" {
-		logrus.Error(err)
+	if err != nil {
+		if err.Error() != "" {
+			logrus.Error(err)
+		}
+		os.Exit(1)
 	}
 }
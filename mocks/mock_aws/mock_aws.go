@@ -14,6 +14,7 @@ import (
 	eks "github.com/aws/aws-sdk-go-v2/service/eks"
 	lambda "github.com/aws/aws-sdk-go-v2/service/lambda"
 	rds "github.com/aws/aws-sdk-go-v2/service/rds"
+	types1 "github.com/aws/aws-sdk-go-v2/service/rds/types"
 	gomock "github.com/golang/mock/gomock"
 	rest "k8s.io/client-go/rest"
 )
@@ -101,6 +102,21 @@ func (mr *MockAWSClientMockRecorder) DescribeRDSClusters() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeRDSClusters", reflect.TypeOf((*MockAWSClient)(nil).DescribeRDSClusters))
 }
 
+// DescribeRDSEngineVersions mocks base method.
+func (m *MockAWSClient) DescribeRDSEngineVersions(engine string) ([]types1.DBEngineVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeRDSEngineVersions", engine)
+	ret0, _ := ret[0].([]types1.DBEngineVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeRDSEngineVersions indicates an expected call of DescribeRDSEngineVersions.
+func (mr *MockAWSClientMockRecorder) DescribeRDSEngineVersions(engine interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeRDSEngineVersions", reflect.TypeOf((*MockAWSClient)(nil).DescribeRDSEngineVersions), engine)
+}
+
 // GetAccountId mocks base method.
 func (m *MockAWSClient) GetAccountId() string {
 	m.ctrl.T.Helper()
@@ -188,6 +204,21 @@ func (mr *MockAWSClientMockRecorder) GetProfile() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProfile", reflect.TypeOf((*MockAWSClient)(nil).GetProfile))
 }
 
+// ListACMCertificateTags mocks base method.
+func (m *MockAWSClient) ListACMCertificateTags(arn string) ([]types.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListACMCertificateTags", arn)
+	ret0, _ := ret[0].([]types.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListACMCertificateTags indicates an expected call of ListACMCertificateTags.
+func (mr *MockAWSClientMockRecorder) ListACMCertificateTags(arn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListACMCertificateTags", reflect.TypeOf((*MockAWSClient)(nil).ListACMCertificateTags), arn)
+}
+
 // ListACMCertificates mocks base method.
 func (m *MockAWSClient) ListACMCertificates() ([]types.CertificateSummary, error) {
 	m.ctrl.T.Helper()
@@ -248,6 +279,21 @@ func (mr *MockAWSClientMockRecorder) ListLambdaFunctions() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLambdaFunctions", reflect.TypeOf((*MockAWSClient)(nil).ListLambdaFunctions))
 }
 
+// ListLambdaTags mocks base method.
+func (m *MockAWSClient) ListLambdaTags(arn string) (map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLambdaTags", arn)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLambdaTags indicates an expected call of ListLambdaTags.
+func (mr *MockAWSClientMockRecorder) ListLambdaTags(arn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLambdaTags", reflect.TypeOf((*MockAWSClient)(nil).ListLambdaTags), arn)
+}
+
 // ListVolumes mocks base method.
 func (m *MockAWSClient) ListVolumes() ([]types0.Volume, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,138 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+	"github.com/stretchr/testify/require"
+)
+
+func eks(id string, status types.Status) types.Versioned {
+	return types.EKSCluster{VersionedResource: types.VersionedResource{Kind: types.KindEKSCluster, ID: id, Version: "1.27", EOL: types.EOLStatus{Status: status}}}
+}
+
+func lambda(id string, status types.Status) types.Versioned {
+	return types.Lambda{VersionedResource: types.VersionedResource{Kind: types.KindLambda, ID: id, Version: "python3.8", EOL: types.EOLStatus{Status: status}}}
+}
+
+func snapshot(at time.Time, account string, resources ...types.Versioned) Snapshot {
+	return Snapshot{
+		RecordedAt: at,
+		Source:     "aws",
+		Scope:      account,
+		Report: &types.InventoryReport{
+			Identity:  types.Indentity{AwsAccountNumber: account},
+			Resources: resources,
+		},
+	}
+}
+
+func TestStore(t *testing.T) {
+	r := require.New(t)
+	dir := filepath.Join(t.TempDir(), "history")
+	store := NewStore(dir)
+
+	snapshots, err := store.Snapshots(time.Time{})
+	r.NoError(err)
+	r.Empty(snapshots)
+
+	day := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	r.NoError(store.Record(snapshot(day.AddDate(0, 0, 1), "111", eks("a", types.StatusWarning))))
+	r.NoError(store.Record(snapshot(day, "111", eks("a", types.StatusValid))))
+	r.NoError(store.Record(snapshot(day.AddDate(0, 0, 2), "222", lambda("fn", types.StatusCritical))))
+
+	snapshots, err = store.Snapshots(time.Time{})
+	r.NoError(err)
+	r.Len(snapshots, 3)
+	r.True(snapshots[0].RecordedAt.Equal(day))
+	r.Equal("111", snapshots[0].Scope)
+	r.Equal(types.Status(types.StatusValid), snapshots[0].Report.Resources[0].GetVersionedResource().EOL.Status)
+
+	snapshots, err = store.Snapshots(day.AddDate(0, 0, 1))
+	r.NoError(err)
+	r.Len(snapshots, 2)
+
+	r.NoError(os.WriteFile(filepath.Join(dir, "20240301T000000.000000000Z-broken.json"), []byte("{"), 0644))
+	_, err = store.Snapshots(time.Time{})
+	r.Error(err)
+}
+
+func TestTrend(t *testing.T) {
+	r := require.New(t)
+	day := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	snapshots := []Snapshot{
+		snapshot(day, "111", eks("a", types.StatusCritical), lambda("fn", types.StatusWarning)),
+		// Only the latest snapshot of an account counts within a day
+		snapshot(day.Add(time.Hour), "111", eks("a", types.StatusValid), lambda("fn", types.StatusWarning)),
+		snapshot(day.Add(time.Hour), "222", eks("b", types.StatusWarning)),
+		snapshot(day.AddDate(0, 0, 1), "111", eks("a", types.StatusValid), lambda("fn", types.StatusValid)),
+	}
+
+	trend, err := Trend(snapshots, "kind", "day")
+	r.NoError(err)
+	r.Equal([]TrendPoint{
+		{Period: "2024-03-01", Group: "eks", Valid: 1, Warning: 1},
+		{Period: "2024-03-01", Group: "lambda", Warning: 1},
+		{Period: "2024-03-02", Group: "eks", Valid: 1},
+		{Period: "2024-03-02", Group: "lambda", Valid: 1},
+	}, trend)
+
+	trend, err = Trend(snapshots, "account", "month")
+	r.NoError(err)
+	r.Equal([]TrendPoint{
+		{Period: "2024-03", Group: "111", Valid: 2},
+		{Period: "2024-03", Group: "222", Warning: 1},
+	}, trend)
+
+	tagged := snapshot(day, "333", eks("c", types.StatusValid), eks("d", types.StatusWarning))
+	tagged.Report.Resources[0] = types.EKSCluster{VersionedResource: types.VersionedResource{Kind: types.KindEKSCluster, ID: "c", Labels: map[string]string{"team": "payments"}}}
+	trend, err = Trend([]Snapshot{tagged}, "tag.team", "week")
+	r.NoError(err)
+	r.Equal([]TrendPoint{
+		{Period: "2024-W09", Group: "-", Warning: 1},
+		{Period: "2024-W09", Group: "payments", Valid: 1},
+	}, trend)
+
+	tagged.Report.Resources[1] = types.EKSCluster{VersionedResource: types.VersionedResource{Kind: types.KindEKSCluster, ID: "d", Owner: "infra"}}
+	trend, err = Trend([]Snapshot{tagged}, "owner", "day")
+	r.NoError(err)
+	r.Equal([]TrendPoint{
+		{Period: "2024-03-01", Group: "(unowned)", Valid: 1},
+		{Period: "2024-03-01", Group: "infra", Valid: 1},
+	}, trend)
+
+	_, err = Trend(snapshots, "color", "day")
+	r.Error(err)
+	_, err = Trend(snapshots, "kind", "hour")
+	r.Error(err)
+}
+
+func TestAges(t *testing.T) {
+	r := require.New(t)
+	day := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	incomplete := snapshot(day.AddDate(0, 0, 3), "111", eks("a", types.StatusCritical))
+	incomplete.Report.Errors = []types.ScrapeError{{Message: "throttled"}}
+	snapshots := []Snapshot{
+		snapshot(day, "111", eks("a", types.StatusValid), lambda("fn", types.StatusWarning)),
+		snapshot(day.AddDate(0, 0, 1), "111", eks("a", types.StatusWarning)),
+		snapshot(day.AddDate(0, 0, 2), "111", eks("a", types.StatusCritical), lambda("fn", types.StatusWarning)),
+		incomplete,
+		snapshot(day.AddDate(0, 0, 4), "111", eks("a", types.StatusCritical), lambda("fn", types.StatusWarning), eks("ok", types.StatusValid)),
+	}
+
+	ages := Ages(snapshots, day.AddDate(0, 0, 10))
+	r.Len(ages, 2)
+
+	r.Equal("a", ages[0].Resource.ID)
+	r.True(ages[0].Since.Equal(day.AddDate(0, 0, 1)))
+	r.True(ages[0].StatusSince.Equal(day.AddDate(0, 0, 2)))
+	r.Equal(9, ages[0].Days)
+
+	// The lambda went missing from a complete snapshot, which ends its streak
+	r.Equal("fn", ages[1].Resource.ID)
+	r.True(ages[1].Since.Equal(day.AddDate(0, 0, 2)))
+	r.Equal(8, ages[1].Days)
+}
@@ -0,0 +1,124 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+)
+
+const timestampFormat = "20060102T150405.000000000Z"
+
+// Snapshot is a report recorded after a scrape. Source is the scraper (aws, github, tfc) and Scope
+// what it scraped (an account, an org), so that the latest snapshot of each scope can be picked.
+type Snapshot struct {
+	RecordedAt time.Time              `json:"recorded_at"`
+	Source     string                 `json:"source"`
+	Scope      string                 `json:"scope,omitempty"`
+	Report     *types.InventoryReport `json:"report"`
+}
+
+func (s Snapshot) key() string {
+	return s.Source + "/" + s.Scope
+}
+
+// Store keeps one JSON file per snapshot in a directory
+type Store struct {
+	dir string
+}
+
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// DefaultDir is ~/.camelot/history
+func DefaultDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".camelot", "history")
+	}
+	return filepath.Join(home, ".camelot", "history")
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+func (s *Store) Record(snapshot Snapshot) error {
+	err := os.MkdirAll(s.dir, 0755)
+	if err != nil {
+		return fmt.Errorf("unable to create history dir %s: %w", s.dir, err)
+	}
+	b, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("unable to marshal snapshot: %w", err)
+	}
+
+	name := snapshot.RecordedAt.UTC().Format(timestampFormat) + "-" + unsafeFileChars.ReplaceAllString(snapshot.key(), "_") + ".json"
+	tmp, err := os.CreateTemp(s.dir, ".snapshot-*")
+	if err != nil {
+		return fmt.Errorf("unable to write snapshot: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(b)
+	if err != nil {
+		tmp.Close()
+		return fmt.Errorf("unable to write snapshot: %w", err)
+	}
+	err = tmp.Close()
+	if err != nil {
+		return fmt.Errorf("unable to write snapshot: %w", err)
+	}
+	err = os.Rename(tmp.Name(), filepath.Join(s.dir, name))
+	if err != nil {
+		return fmt.Errorf("unable to write snapshot: %w", err)
+	}
+	return nil
+}
+
+// Snapshots returns the snapshots recorded at or after since, oldest first
+func (s *Store) Snapshots(since time.Time) ([]Snapshot, error) {
+	entries, err := os.ReadDir(s.dir)
+	if os.IsNotExist(err) {
+		return []Snapshot{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read history dir %s: %w", s.dir, err)
+	}
+
+	snapshots := []Snapshot{}
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		// File names start with the timestamp, so old snapshots are skipped without decoding them
+		recordedAt, err := time.Parse(timestampFormat, strings.SplitN(entry.Name(), "-", 2)[0])
+		if err == nil && recordedAt.Before(since) {
+			continue
+		}
+
+		path := filepath.Join(s.dir, entry.Name())
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read snapshot %s: %w", path, err)
+		}
+		snapshot := Snapshot{}
+		err = json.Unmarshal(b, &snapshot)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode snapshot %s: %w", path, err)
+		}
+		if snapshot.Report == nil || snapshot.RecordedAt.Before(since) {
+			continue
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		return snapshots[i].RecordedAt.Before(snapshots[j].RecordedAt)
+	})
+	return snapshots, nil
+}
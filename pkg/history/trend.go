@@ -0,0 +1,209 @@
+package history
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+	"github.com/chanzuckerberg/camelot/pkg/util"
+)
+
+// TrendPoint counts resources by status in one group at the end of an interval
+type TrendPoint struct {
+	Period   string `json:"period"`
+	Group    string `json:"group"`
+	Valid    int    `json:"valid"`
+	Warning  int    `json:"warning"`
+	Critical int    `json:"critical"`
+}
+
+func (p TrendPoint) Total() int {
+	return p.Valid + p.Warning + p.Critical
+}
+
+type grouper func(snapshot Snapshot, item types.VersionedResource) string
+
+var groupers = map[string]grouper{
+	"kind": func(snapshot Snapshot, item types.VersionedResource) string {
+		return string(item.Kind)
+	},
+	"account": func(snapshot Snapshot, item types.VersionedResource) string {
+		if len(snapshot.Report.Identity.AwsAccountNumber) > 0 {
+			return snapshot.Report.Identity.AwsAccountNumber
+		}
+		return snapshot.key()
+	},
+	"source": func(snapshot Snapshot, item types.VersionedResource) string {
+		return snapshot.Source
+	},
+	"owner": func(snapshot Snapshot, item types.VersionedResource) string {
+		if len(item.Owner) == 0 {
+			return util.Unowned
+		}
+		return item.Owner
+	},
+}
+
+// GroupKeys lists the supported values of the `by` argument of Trend
+func GroupKeys() []string {
+	keys := []string{}
+	for key := range groupers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return append(keys, "tag.<key>")
+}
+
+func getGrouper(by string) (grouper, bool) {
+	if tag, ok := strings.CutPrefix(by, "tag."); ok && len(tag) > 0 {
+		return func(snapshot Snapshot, item types.VersionedResource) string {
+			if value, ok := item.Labels[tag]; ok {
+				return value
+			}
+			return "-"
+		}, true
+	}
+	group, ok := groupers[by]
+	return group, ok
+}
+
+var periods = map[string]func(t time.Time) string{
+	"day": func(t time.Time) string {
+		return t.UTC().Format("2006-01-02")
+	},
+	"week": func(t time.Time) string {
+		year, week := t.UTC().ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	},
+	"month": func(t time.Time) string {
+		return t.UTC().Format("2006-01")
+	},
+}
+
+// Trend counts the resources by status per interval (day, week or month) and group. Within an interval
+// only the latest snapshot of each source and scope is counted.
+func Trend(snapshots []Snapshot, by string, interval string) ([]TrendPoint, error) {
+	group, ok := getGrouper(by)
+	if !ok {
+		return nil, fmt.Errorf("unsupported grouping %q, expected one of %s", by, strings.Join(GroupKeys(), ", "))
+	}
+	period, ok := periods[interval]
+	if !ok {
+		return nil, fmt.Errorf("unsupported interval %q, expected day, week or month", interval)
+	}
+
+	type bucket struct {
+		period string
+		key    string
+	}
+	latest := map[bucket]Snapshot{}
+	for _, snapshot := range snapshots {
+		b := bucket{period: period(snapshot.RecordedAt), key: snapshot.key()}
+		if s, ok := latest[b]; !ok || !snapshot.RecordedAt.Before(s.RecordedAt) {
+			latest[b] = snapshot
+		}
+	}
+
+	type point struct {
+		period string
+		group  string
+	}
+	points := map[point]*TrendPoint{}
+	for b, snapshot := range latest {
+		for _, resource := range snapshot.Report.Resources {
+			item := resource.GetVersionedResource()
+			p := point{period: b.period, group: group(snapshot, item)}
+			if _, ok := points[p]; !ok {
+				points[p] = &TrendPoint{Period: p.period, Group: p.group}
+			}
+			switch item.EOL.Status {
+			case types.StatusCritical:
+				points[p].Critical++
+			case types.StatusWarning:
+				points[p].Warning++
+			default:
+				points[p].Valid++
+			}
+		}
+	}
+
+	trend := []TrendPoint{}
+	for _, p := range points {
+		trend = append(trend, *p)
+	}
+	sort.Slice(trend, func(i, j int) bool {
+		if trend[i].Period != trend[j].Period {
+			return trend[i].Period < trend[j].Period
+		}
+		return trend[i].Group < trend[j].Group
+	})
+	return trend, nil
+}
+
+// ResourceAge tells how long a resource which is WARNING or CRITICAL in the latest snapshot has been
+// out of VALID status (Since), and how long it has been in its current status (StatusSince)
+type ResourceAge struct {
+	Resource    types.VersionedResource `json:"resource"`
+	Since       time.Time               `json:"since"`
+	StatusSince time.Time               `json:"status_since"`
+	Days        int                     `json:"days"`
+}
+
+// Ages walks back from the latest snapshot of each source and scope. A resource missing from a
+// complete snapshot, or seen VALID, ends the streak; incomplete snapshots without it are skipped.
+func Ages(snapshots []Snapshot, now time.Time) []ResourceAge {
+	byKey := map[string][]Snapshot{}
+	for _, snapshot := range snapshots {
+		byKey[snapshot.key()] = append(byKey[snapshot.key()], snapshot)
+	}
+
+	ages := []ResourceAge{}
+	for _, scoped := range byKey {
+		indexes := make([]map[string]types.VersionedResource, len(scoped))
+		for i, snapshot := range scoped {
+			indexes[i] = map[string]types.VersionedResource{}
+			for _, resource := range snapshot.Report.Resources {
+				item := resource.GetVersionedResource()
+				indexes[i][util.ResourceIdentity(item)] = item
+			}
+		}
+
+		last := len(scoped) - 1
+		for identity, item := range indexes[last] {
+			if item.EOL.Status != types.StatusWarning && item.EOL.Status != types.StatusCritical {
+				continue
+			}
+			age := ResourceAge{Resource: item, Since: scoped[last].RecordedAt, StatusSince: scoped[last].RecordedAt}
+			sameStatus := true
+			for i := last - 1; i >= 0; i-- {
+				previous, ok := indexes[i][identity]
+				if !ok {
+					if scoped[i].Report.Complete() {
+						break
+					}
+					continue
+				}
+				if previous.EOL.Status != types.StatusWarning && previous.EOL.Status != types.StatusCritical {
+					break
+				}
+				age.Since = scoped[i].RecordedAt
+				sameStatus = sameStatus && previous.EOL.Status == item.EOL.Status
+				if sameStatus {
+					age.StatusSince = scoped[i].RecordedAt
+				}
+			}
+			age.Days = int(now.Sub(age.Since).Hours() / 24)
+			ages = append(ages, age)
+		}
+	}
+
+	sort.SliceStable(ages, func(i, j int) bool {
+		if !ages[i].Since.Equal(ages[j].Since) {
+			return ages[i].Since.Before(ages[j].Since)
+		}
+		return util.ResourceIdentity(ages[i].Resource) < util.ResourceIdentity(ages[j].Resource)
+	})
+	return ages
+}
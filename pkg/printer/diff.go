@@ -0,0 +1,89 @@
+package printer
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/chanzuckerberg/camelot/pkg/util"
+)
+
+var diffHeader = []string{"Change", "Kind", "Name", "Parent", "Version", "Status"}
+
+func PrintDiff(diff util.ReportDiff, outputFormat string) error {
+	writer := bufio.NewWriter(os.Stdout)
+	defer writer.Flush()
+
+	switch outputFormat {
+	case "json":
+		b, err := json.MarshalIndent(diff, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal json diff: %w", err)
+		}
+		_, err = writer.Write(b)
+		if err != nil {
+			return fmt.Errorf("failed to write json diff: %w", err)
+		}
+	case "markdown":
+		_, err := writer.WriteString(diffToMarkdown(diff))
+		if err != nil {
+			return fmt.Errorf("failed to write markdown diff: %w", err)
+		}
+	default:
+		_, err := writer.WriteString(fmt.Sprintf("\n%s\n\n", diffSummary(diff)))
+		if err != nil {
+			return fmt.Errorf("failed to write diff: %w", err)
+		}
+		if len(diff.Changes) == 0 {
+			return nil
+		}
+		writer.Flush()
+
+		table := newTable(os.Stdout, diffHeader)
+		table.AppendBulk(util.DiffToTable(diff))
+		table.Render()
+	}
+	return nil
+}
+
+func diffSummary(diff util.ReportDiff) string {
+	summary := diff.Summary()
+	counts := []string{}
+	for _, change := range util.ChangeTypes {
+		if summary[change] > 0 {
+			counts = append(counts, fmt.Sprintf("%d %s", summary[change], change))
+		}
+	}
+	if len(counts) == 0 {
+		return "No changes"
+	}
+	return "Changes: " + strings.Join(counts, ", ")
+}
+
+func diffToMarkdown(diff util.ReportDiff) string {
+	var sb strings.Builder
+	sb.WriteString("## Inventory changes\n\n")
+	sb.WriteString(diffSummary(diff))
+	sb.WriteString("\n")
+	if len(diff.Changes) == 0 {
+		return sb.String()
+	}
+
+	sb.WriteString("\n")
+	writeMarkdownTable(&sb, diffHeader, util.DiffToTable(diff))
+	return sb.String()
+}
+
+var markdownEscaper = strings.NewReplacer("|", "\\|", "\n", " ")
+
+func writeMarkdownRow(sb *strings.Builder, cells []string) {
+	sb.WriteString("|")
+	for _, cell := range cells {
+		sb.WriteString(" ")
+		sb.WriteString(markdownEscaper.Replace(cell))
+		sb.WriteString(" |")
+	}
+	sb.WriteString("\n")
+}
@@ -0,0 +1,127 @@
+package printer
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+	"github.com/chanzuckerberg/camelot/pkg/util"
+)
+
+var statusColors = map[types.Status]string{
+	types.StatusValid:    "#b7e1cd",
+	types.StatusWarning:  "#fce8b2",
+	types.StatusCritical: "#f4c7c3",
+}
+
+const noStatusColor = "#e0e0e0"
+
+func isGraphFormat(outputFormat string) bool {
+	return outputFormat == "dot" || outputFormat == "mermaid" || outputFormat == "graph-json"
+}
+
+func printGraph(w io.Writer, graph util.Graph, outputFormat string) error {
+	var out string
+	switch outputFormat {
+	case "graph-json":
+		return printJSON(w, graph)
+	case "dot":
+		out = graphToDot(graph)
+	case "mermaid":
+		out = graphToMermaid(graph)
+	default:
+		return fmt.Errorf("unsupported graph format %q", outputFormat)
+	}
+	_, err := io.WriteString(w, out)
+	if err != nil {
+		return fmt.Errorf("failed to write graph: %w", err)
+	}
+	return nil
+}
+
+// nodeLabel lines are the kind, name and version of the node
+func nodeLabel(node util.GraphNode) []string {
+	lines := []string{}
+	if len(node.Kind) > 0 {
+		lines = append(lines, string(node.Kind))
+	}
+	lines = append(lines, node.Name)
+	if len(node.Version) > 0 {
+		lines = append(lines, node.Version)
+	}
+	return lines
+}
+
+func nodeColor(node util.GraphNode) string {
+	if color, ok := statusColors[node.Status]; ok {
+		return color
+	}
+	return noStatusColor
+}
+
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+func graphToDot(graph util.Graph) string {
+	var sb strings.Builder
+	sb.WriteString("digraph camelot {\n")
+	for _, e := range graph.Errors {
+		fmt.Fprintf(&sb, "  // error: %s\n", util.FormatScrapeError(e))
+	}
+	sb.WriteString("  rankdir=LR;\n")
+	if len(graph.Errors) > 0 {
+		fmt.Fprintf(&sb, "  labelloc=\"t\";\n  label=\"%s\";\n", incompleteNote(len(graph.Errors)))
+	}
+	sb.WriteString("  node [shape=box, style=\"rounded,filled\", fontname=\"Helvetica\"];\n")
+	for _, node := range graph.Nodes {
+		lines := nodeLabel(node)
+		for i, line := range lines {
+			lines[i] = dotEscaper.Replace(line)
+		}
+		fmt.Fprintf(&sb, "  \"%s\" [label=\"%s\", fillcolor=\"%s\"];\n", dotEscaper.Replace(node.ID), strings.Join(lines, `\n`), nodeColor(node))
+	}
+	for _, edge := range graph.Edges {
+		fmt.Fprintf(&sb, "  \"%s\" -> \"%s\";\n", dotEscaper.Replace(edge.From), dotEscaper.Replace(edge.To))
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+var mermaidEscaper = strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;")
+
+// graphToMermaid numbers the nodes, as mermaid ids cannot hold the characters of resource ids
+func graphToMermaid(graph util.Graph) string {
+	ids := map[string]string{}
+	var sb strings.Builder
+	sb.WriteString("graph LR\n")
+	for _, e := range graph.Errors {
+		fmt.Fprintf(&sb, "  %%%% error: %s\n", util.FormatScrapeError(e))
+	}
+	// A node standing out from the resources, as mermaid graphs have no title
+	if len(graph.Errors) > 0 {
+		fmt.Fprintf(&sb, "  errors[\"%s\"]:::%s\n", incompleteNote(len(graph.Errors)), strings.ToLower(string(types.StatusCritical)))
+	}
+	for i, node := range graph.Nodes {
+		ids[node.ID] = fmt.Sprintf("n%d", i)
+		lines := nodeLabel(node)
+		for j, line := range lines {
+			lines[j] = mermaidEscaper.Replace(line)
+		}
+		fmt.Fprintf(&sb, "  %s[\"%s\"]:::%s\n", ids[node.ID], strings.Join(lines, "<br/>"), mermaidClass(node))
+	}
+	for _, edge := range graph.Edges {
+		fmt.Fprintf(&sb, "  %s --> %s\n", ids[edge.From], ids[edge.To])
+	}
+	for _, status := range []types.Status{types.StatusValid, types.StatusWarning, types.StatusCritical} {
+		fmt.Fprintf(&sb, "  classDef %s fill:%s\n", strings.ToLower(string(status)), statusColors[status])
+	}
+	fmt.Fprintf(&sb, "  classDef none fill:%s\n", noStatusColor)
+	return sb.String()
+}
+
+func mermaidClass(node util.GraphNode) string {
+	if _, ok := statusColors[node.Status]; ok {
+		return strings.ToLower(string(node.Status))
+	}
+	return "none"
+}
@@ -0,0 +1,66 @@
+package printer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/chanzuckerberg/camelot/pkg/history"
+	"github.com/chanzuckerberg/camelot/pkg/util"
+)
+
+func PrintTrend(trend []history.TrendPoint, outputFormat string) error {
+	if outputFormat == "json" {
+		return printJSON(os.Stdout, trend)
+	}
+
+	table := newTable(os.Stdout, []string{"Period", "Group", "Valid", "Warning", "Critical", "Total"})
+	for _, p := range trend {
+		table.Append([]string{
+			p.Period,
+			p.Group,
+			strconv.Itoa(p.Valid),
+			strconv.Itoa(p.Warning),
+			strconv.Itoa(p.Critical),
+			strconv.Itoa(p.Total()),
+		})
+	}
+	table.Render()
+	return nil
+}
+
+func PrintAges(ages []history.ResourceAge, outputFormat string) error {
+	if outputFormat == "json" {
+		return printJSON(os.Stdout, ages)
+	}
+
+	table := newTable(os.Stdout, []string{"Kind", "Name", "Parent", "Version", "Status", "Status Since", "Out of VALID Since", "Days"})
+	for _, age := range ages {
+		table.Append([]string{
+			string(age.Resource.Kind),
+			age.Resource.ID,
+			util.FormatParents(age.Resource.Parents),
+			age.Resource.Version,
+			string(age.Resource.EOL.Status),
+			age.StatusSince.Format("2006-01-02"),
+			age.Since.Format("2006-01-02"),
+			strconv.Itoa(age.Days),
+		})
+	}
+	table.Render()
+	return nil
+}
+
+func printJSON(w io.Writer, v interface{}) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal json: %w", err)
+	}
+	_, err = w.Write(append(b, '\n'))
+	if err != nil {
+		return fmt.Errorf("failed to write json: %w", err)
+	}
+	return nil
+}
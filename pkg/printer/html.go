@@ -0,0 +1,150 @@
+package printer
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"time"
+
+	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+	"github.com/chanzuckerberg/camelot/pkg/util"
+)
+
+//go:embed templates/report.html
+var htmlTemplate string
+
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"status":  statusClass,
+	"parents": util.FormatParents,
+	"path":    util.FormatUpgradePath,
+}).Parse(htmlTemplate))
+
+// htmlBreakdown is a row of the per account and per owner tables
+type htmlBreakdown struct {
+	Key      string
+	Total    int
+	Critical int
+	Warning  int
+	Valid    int
+}
+
+type htmlBreakdownTable struct {
+	Field string
+	Rows  []htmlBreakdown
+}
+
+type htmlKind struct {
+	Kind      types.ResourceKind
+	Resources []types.VersionedResource
+}
+
+type htmlMonth struct {
+	Month     string
+	Resources []types.VersionedResource
+}
+
+type htmlReport struct {
+	GeneratedAt string
+	Account     string
+	Summary     htmlBreakdown
+	Kinds       []htmlKind
+	Breakdowns  []htmlBreakdownTable
+	Timeline    []htmlMonth
+	PastEOL     int
+	Errors      [][]string
+}
+
+func statusClass(status types.Status) string {
+	switch status {
+	case types.StatusCritical:
+		return "critical"
+	case types.StatusWarning:
+		return "warning"
+	case types.StatusValid:
+		return "valid"
+	}
+	return "unknown"
+}
+
+func breakdown(key string, resources []types.Versioned) htmlBreakdown {
+	b := htmlBreakdown{Key: key, Total: len(resources)}
+	for _, item := range resources {
+		switch item.GetVersionedResource().EOL.Status {
+		case types.StatusCritical:
+			b.Critical++
+		case types.StatusWarning:
+			b.Warning++
+		case types.StatusValid:
+			b.Valid++
+		}
+	}
+	return b
+}
+
+func breakdowns(report types.InventoryReport, field string) (htmlBreakdownTable, error) {
+	table := htmlBreakdownTable{Field: field}
+	groups, err := util.GroupReport(report, field)
+	if err != nil {
+		return table, err
+	}
+	for _, group := range groups {
+		table.Rows = append(table.Rows, breakdown(group.Key, group.Report.Resources))
+	}
+	return table, nil
+}
+
+// newHTMLReport prepares the report for the dashboard: resources by kind, status counts by account and
+// owner, and the resources reaching their end of life, by month
+func newHTMLReport(report types.InventoryReport, now time.Time) (*htmlReport, error) {
+	page := &htmlReport{
+		GeneratedAt: now.UTC().Format("2006-01-02 15:04 UTC"),
+		Account:     report.Identity.AwsAccountNumber,
+		Summary:     breakdown("", report.Resources),
+		Errors:      util.ErrorsToTable(report),
+	}
+
+	for _, field := range []string{"account", "owner"} {
+		table, err := breakdowns(report, field)
+		if err != nil {
+			return nil, err
+		}
+		page.Breakdowns = append(page.Breakdowns, table)
+	}
+
+	kinds, err := util.GroupReport(report, "kind")
+	if err != nil {
+		return nil, err
+	}
+	for _, group := range kinds {
+		kind := htmlKind{Kind: types.ResourceKind(group.Key)}
+		for _, item := range group.Report.Resources {
+			kind.Resources = append(kind.Resources, item.GetVersionedResource())
+		}
+		page.Kinds = append(page.Kinds, kind)
+	}
+
+	page.PastEOL = len(util.PastEOL(report, now))
+	for _, resource := range util.UpcomingEOL(report, now) {
+		month := resource.EOL.EOLDate[:7]
+		if len(page.Timeline) == 0 || page.Timeline[len(page.Timeline)-1].Month != month {
+			page.Timeline = append(page.Timeline, htmlMonth{Month: month})
+		}
+		last := &page.Timeline[len(page.Timeline)-1]
+		last.Resources = append(last.Resources, resource)
+	}
+	return page, nil
+}
+
+// printHTML writes a self-contained dashboard, with its styles and scripts inline
+func printHTML(w io.Writer, report types.InventoryReport) error {
+	page, err := newHTMLReport(report, time.Now())
+	if err != nil {
+		return err
+	}
+	err = reportTemplate.Execute(w, page)
+	if err != nil {
+		return fmt.Errorf("failed to write html report: %w", err)
+	}
+	return nil
+}
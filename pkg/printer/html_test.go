@@ -0,0 +1,110 @@
+package printer
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+	"github.com/chanzuckerberg/camelot/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func htmlResource(kind types.ResourceKind, id, account, owner, eolDate string, status types.Status) types.Versioned {
+	resource := types.VersionedResource{Kind: kind, ID: id, Owner: owner, Version: "1.0", EOL: types.EOLStatus{EOLDate: eolDate, Status: status}}
+	if len(account) > 0 {
+		resource.Parents = []types.ParentResource{{Kind: types.KindAWSAccount, ID: account}}
+	}
+	return types.EKSCluster{VersionedResource: resource}
+}
+
+func TestNewHTMLReport(t *testing.T) {
+	r := require.New(t)
+	now := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		resources []types.Versioned
+		pastEOL   int
+		timeline  map[string][]string
+		months    []string
+		summary   htmlBreakdown
+		accounts  []htmlBreakdown
+		owners    []htmlBreakdown
+	}{
+		{
+			name:     "empty",
+			timeline: map[string][]string{},
+			months:   nil,
+			summary:  htmlBreakdown{},
+		},
+		{
+			name: "past and upcoming end of life",
+			resources: []types.Versioned{
+				htmlResource(types.KindEKSCluster, "expired", "111", "platform", "2024-01-05", types.StatusCritical),
+				htmlResource(types.KindEKSCluster, "flagged", "111", "platform", "true", types.StatusCritical),
+				htmlResource(types.KindEKSCluster, "today", "222", "", "2024-01-10", types.StatusCritical),
+				htmlResource(types.KindEKSCluster, "january", "222", "data", "2024-01-31", types.StatusWarning),
+				htmlResource(types.KindEKSCluster, "february-late", "111", "data", "2024-02-20", types.StatusWarning),
+				htmlResource(types.KindEKSCluster, "february", "", "data", "2024-02-01", types.StatusValid),
+				htmlResource(types.KindEKSCluster, "next-year", "222", "", "2025-11-13", types.StatusValid),
+				htmlResource(types.KindEKSCluster, "undated", "222", "", "", types.StatusValid),
+			},
+			pastEOL: 2,
+			months:  []string{"2024-01", "2024-02", "2025-11"},
+			timeline: map[string][]string{
+				"2024-01": {"today", "january"},
+				"2024-02": {"february", "february-late"},
+				"2025-11": {"next-year"},
+			},
+			summary: htmlBreakdown{Total: 8, Critical: 3, Warning: 2, Valid: 3},
+			accounts: []htmlBreakdown{
+				{Key: "111", Total: 3, Critical: 2, Warning: 1},
+				{Key: "222", Total: 4, Critical: 1, Warning: 1, Valid: 2},
+				{Key: util.NoValue, Total: 1, Valid: 1},
+			},
+			owners: []htmlBreakdown{
+				{Key: "data", Total: 3, Warning: 2, Valid: 1},
+				{Key: "platform", Total: 2, Critical: 2},
+				{Key: util.Unowned, Total: 3, Critical: 1, Valid: 2},
+			},
+		},
+	}
+	for _, test := range tests {
+		page, err := newHTMLReport(types.InventoryReport{Resources: test.resources}, now)
+		r.NoError(err, test.name)
+		r.Equal("2024-01-10 12:00 UTC", page.GeneratedAt, test.name)
+		r.Equal(test.pastEOL, page.PastEOL, test.name)
+		r.Equal(test.summary, page.Summary, test.name)
+
+		months := []string(nil)
+		timeline := map[string][]string{}
+		for _, month := range page.Timeline {
+			months = append(months, month.Month)
+			for _, resource := range month.Resources {
+				timeline[month.Month] = append(timeline[month.Month], resource.ID)
+			}
+		}
+		r.Equal(test.months, months, test.name)
+		r.Equal(test.timeline, timeline, test.name)
+
+		r.Len(page.Breakdowns, 2, test.name)
+		r.Equal("account", page.Breakdowns[0].Field, test.name)
+		r.Equal(test.accounts, page.Breakdowns[0].Rows, test.name)
+		r.Equal("owner", page.Breakdowns[1].Field, test.name)
+		r.Equal(test.owners, page.Breakdowns[1].Rows, test.name)
+	}
+}
+
+func TestPrintHTML(t *testing.T) {
+	r := require.New(t)
+	report := types.InventoryReport{
+		Resources: []types.Versioned{htmlResource(types.KindEKSCluster, "<script>alert(1)</script>", "111", "platform", "2099-01-01", types.StatusValid)},
+		Errors:    []types.ScrapeError{{Source: "aws", Message: "access denied"}},
+	}
+	var b bytes.Buffer
+	r.NoError(printHTML(&b, report))
+	r.NotContains(b.String(), "<script>alert(1)</script>")
+	r.Contains(b.String(), "&lt;script&gt;alert(1)&lt;/script&gt;")
+	r.Contains(b.String(), "access denied")
+}
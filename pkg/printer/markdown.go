@@ -0,0 +1,164 @@
+package printer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+	"github.com/chanzuckerberg/camelot/pkg/util"
+)
+
+var statusEmoji = map[types.Status]string{
+	types.StatusCritical: "🔴",
+	types.StatusWarning:  "🟡",
+	types.StatusValid:    "🟢",
+}
+
+func statusBadge(status types.Status) string {
+	emoji, ok := statusEmoji[status]
+	if !ok {
+		emoji = "⚪"
+	}
+	if len(status) == 0 {
+		return emoji
+	}
+	return emoji + " " + string(status)
+}
+
+// markdownSubtotal reads e.g. "5 resources: 🔴 1 CRITICAL, 🟡 2 WARNING, 🟢 2 VALID"
+func markdownSubtotal(group util.ResourceGroup) string {
+	counts := group.StatusCounts()
+	subtotal := fmt.Sprintf("%d resources", len(group.Report.Resources))
+	if len(group.Report.Resources) == 1 {
+		subtotal = "1 resource"
+	}
+	parts := []string{}
+	for _, status := range []types.Status{types.StatusCritical, types.StatusWarning, types.StatusValid} {
+		if counts[status] > 0 {
+			parts = append(parts, fmt.Sprintf("%s %d %s", statusEmoji[status], counts[status], status))
+		}
+	}
+	if len(parts) > 0 {
+		subtotal += ": " + strings.Join(parts, ", ")
+	}
+	return subtotal
+}
+
+func markdownLink(text, url string) string {
+	if len(text) == 0 || len(url) == 0 {
+		return text
+	}
+	return "[" + text + "](" + url + ")"
+}
+
+func printMarkdown(w io.Writer, report types.InventoryReport, groups []util.ResourceGroup, options *printOptions) error {
+	writer := bufio.NewWriter(w)
+	_, err := writer.WriteString(reportToMarkdown(report, groups, options))
+	if err != nil {
+		return fmt.Errorf("failed to write markdown report: %w", err)
+	}
+	return writer.Flush()
+}
+
+// reportToMarkdown renders GitHub-flavored Markdown: a collapsible table per kind, under a heading per
+// group when the report is grouped
+func reportToMarkdown(report types.InventoryReport, groups []util.ResourceGroup, options *printOptions) string {
+	var sb strings.Builder
+	sb.WriteString("## Inventory report\n\n")
+	if len(report.Identity.AwsAccountNumber) > 0 {
+		sb.WriteString(fmt.Sprintf("Account: %s\n\n", report.Identity.AwsAccountNumber))
+	}
+	sb.WriteString(markdownSubtotal(util.ResourceGroup{Report: report}))
+	sb.WriteString("\n")
+
+	if groups == nil {
+		writeMarkdownKinds(&sb, report, options)
+	}
+	for _, group := range groups {
+		sb.WriteString(fmt.Sprintf("\n### %s: %s\n\n%s\n", options.groupBy, group.Key, markdownSubtotal(group)))
+		writeMarkdownKinds(&sb, group.Report, options)
+	}
+
+	if !report.Complete() {
+		sb.WriteString("\n### Errors\n\nThe report is incomplete.\n\n")
+		writeMarkdownTable(&sb, []string{"Source", "Extractor", "Account", "Region", "Resource", "Message"}, util.ErrorsToTable(report))
+	}
+	return sb.String()
+}
+
+// writeMarkdownKinds writes a <details> section per kind, open when the kind has CRITICAL resources
+func writeMarkdownKinds(sb *strings.Builder, report types.InventoryReport, options *printOptions) {
+	kinds, err := util.GroupReport(report, "kind")
+	if err != nil {
+		return
+	}
+
+	header := []string{"Status", "Name", "Parent", "Version", "Current", "EOL Date", "Owner", "Source"}
+	for _, tag := range options.tagColumns {
+		header = append(header, "Tag:"+tag)
+	}
+	if options.upgradePath {
+		header = append(header, "Upgrade Path")
+	}
+
+	for _, kind := range kinds {
+		open := ""
+		if kind.StatusCounts()[types.StatusCritical] > 0 {
+			open = " open"
+		}
+		sb.WriteString(fmt.Sprintf("\n<details%s>\n<summary><b>%s</b> (%s)</summary>\n\n", open, kind.Key, markdownSubtotal(kind)))
+
+		rows := [][]string{}
+		for _, item := range kind.Report.Resources {
+			resource := item.GetVersionedResource()
+			row := []string{
+				statusBadge(resource.EOL.Status),
+				markdownLink(resource.ID, util.ConsoleURL(resource.Arn)),
+				util.FormatParents(resource.Parents),
+				resource.Version,
+				resource.CurrentVersion,
+				resource.EOL.EOLDate,
+				resource.Owner,
+				markdownSource(resource.GitOpsReference),
+			}
+			for _, tag := range options.tagColumns {
+				row = append(row, resource.Labels[tag])
+			}
+			if options.upgradePath {
+				row = append(row, util.FormatUpgradePath(resource.Remediation))
+			}
+			rows = append(rows, row)
+		}
+		writeMarkdownTable(sb, header, rows)
+		sb.WriteString("\n</details>\n")
+	}
+}
+
+// markdownSource links the repo and path a resource is managed from, e.g. [infra](...) / [envs/prod](...)
+func markdownSource(ref types.GitOpsReference) string {
+	parts := []string{}
+	if len(ref.Repo) > 0 {
+		parts = append(parts, markdownLink(ref.Repo, util.RepoURL(ref)))
+	}
+	if len(ref.Path) > 0 {
+		parts = append(parts, markdownLink(ref.Path, util.PathURL(ref)))
+	}
+	if len(ref.Workspace) > 0 {
+		parts = append(parts, "workspace "+ref.Workspace)
+	}
+	return strings.Join(parts, " / ")
+}
+
+func writeMarkdownTable(sb *strings.Builder, header []string, rows [][]string) {
+	writeMarkdownRow(sb, header)
+	separator := make([]string, len(header))
+	for i := range separator {
+		separator[i] = "---"
+	}
+	writeMarkdownRow(sb, separator)
+	for _, row := range rows {
+		writeMarkdownRow(sb, row)
+	}
+}
@@ -0,0 +1,49 @@
+package printer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+	"github.com/chanzuckerberg/camelot/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportToMarkdown(t *testing.T) {
+	r := require.New(t)
+	account := []types.ParentResource{{Kind: types.KindAWSAccount, ID: "123"}}
+	report := types.InventoryReport{
+		Identity: types.Indentity{AwsAccountNumber: "123"},
+		Resources: []types.Versioned{
+			types.EKSCluster{VersionedResource: types.VersionedResource{Kind: types.KindEKSCluster, ID: "prod", Parents: account, Version: "1.24",
+				Arn: "arn:aws:eks:us-west-2:123:cluster/prod", Owner: "platform", EOL: types.EOLStatus{EOLDate: "2024-01-31", Status: types.StatusCritical},
+				Labels: map[string]string{"team": "a|b\nc"}}},
+			types.RDSCluster{VersionedResource: types.VersionedResource{Kind: types.KindRDSCluster, ID: "analytics", Parents: account, Version: "13.7",
+				Owner: "data", EOL: types.EOLStatus{EOLDate: "2025-11-13", Status: types.StatusValid},
+				GitOpsReference: types.GitOpsReference{Repo: "chanzuckerberg/infra", Branch: "main", Path: "envs/prod", Workspace: "prod"}}},
+		},
+	}
+
+	md := reportToMarkdown(report, nil, &printOptions{tagColumns: []string{"team"}})
+	r.True(strings.HasPrefix(md, "## Inventory report\n\nAccount: 123\n\n2 resources: 🔴 1 CRITICAL, 🟢 1 VALID\n"))
+	// Kinds with CRITICAL resources are expanded, the others collapsed
+	r.Contains(md, "<details open>\n<summary><b>eks</b> (1 resource: 🔴 1 CRITICAL)</summary>")
+	r.Contains(md, "<details>\n<summary><b>rds</b> (1 resource: 🟢 1 VALID)</summary>")
+	r.Contains(md, "| Status | Name | Parent | Version | Current | EOL Date | Owner | Source | Tag:team |")
+	// Resources link to the console, sources to their repo and path, and cells are escaped
+	r.Contains(md, "| 🔴 CRITICAL | [prod](https://console.aws.amazon.com/go/view?arn=arn%3Aaws%3Aeks%3Aus-west-2%3A123%3Acluster%2Fprod) | aws:123 | 1.24 |  | 2024-01-31 | platform |  | a\\|b c |")
+	r.Contains(md, "| [chanzuckerberg/infra](https://github.com/chanzuckerberg/infra) / [envs/prod](https://github.com/chanzuckerberg/infra/tree/main/envs/prod) / workspace prod |")
+	r.NotContains(md, "### Errors")
+
+	// Grouped reports get a heading per group, and errors are listed last
+	report.Errors = []types.ScrapeError{{Source: "aws", Extractor: "lambda", Account: "123", Message: "access denied"}}
+	groups, err := util.GroupReport(report, "owner")
+	r.NoError(err)
+	md = reportToMarkdown(report, groups, &printOptions{groupBy: "owner"})
+	data := strings.Index(md, "\n### owner: data\n\n1 resource: 🟢 1 VALID\n")
+	platform := strings.Index(md, "\n### owner: platform\n\n1 resource: 🔴 1 CRITICAL\n")
+	errors := strings.Index(md, "\n### Errors\n\nThe report is incomplete.\n")
+	r.True(data > 0 && platform > data && errors > platform, md)
+	r.Contains(md, "| aws | lambda | 123 |  |  | access denied |")
+	r.NotContains(md, "Tag:team")
+}
@@ -2,9 +2,16 @@ package printer
 
 import (
 	"bufio"
+	"encoding/csv"
 	"encoding/json"
+	"encoding/xml"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
 
 	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
 	"github.com/chanzuckerberg/camelot/pkg/util"
@@ -12,26 +19,161 @@ import (
 	"gopkg.in/yaml.v2"
 )
 
-func PrintReport(report *types.InventoryReport, filter util.ReportFilter, outputFormat string) error {
+type printOptions struct {
+	tagColumns    []string
+	groupBy       string
+	collapseValid bool
+	upgradePath   bool
+	sortBy        []string
+	columns       []util.Column
+	soonest       int
+}
+
+type PrintOpt func(*printOptions)
+
+// WithTagColumns adds a text column for each of the given tags
+func WithTagColumns(tags ...string) PrintOpt {
+	return func(o *printOptions) {
+		o.tagColumns = tags
+	}
+}
+
+// WithGroupBy splits the report by a field (e.g. owner), with a status subtotal per group
+func WithGroupBy(field string) PrintOpt {
+	return func(o *printOptions) {
+		o.groupBy = field
+	}
+}
+
+// WithCollapseValid replaces the subtrees of graph output in which every resource is VALID with a count
+func WithCollapseValid(collapse bool) PrintOpt {
+	return func(o *printOptions) {
+		o.collapseValid = collapse
+	}
+}
+
+// WithUpgradePath adds a text column with the recommended upgrade path of each resource
+func WithUpgradePath(show bool) PrintOpt {
+	return func(o *printOptions) {
+		o.upgradePath = show
+	}
+}
+
+// WithSortBy sorts the resources by the given fields, prefixed with - for descending order
+func WithSortBy(fields ...string) PrintOpt {
+	return func(o *printOptions) {
+		o.sortBy = fields
+	}
+}
+
+// WithSoonest sets the number of soonest expiring resources of the summary output
+func WithSoonest(n int) PrintOpt {
+	return func(o *printOptions) {
+		o.soonest = n
+	}
+}
+
+// PrintReport writes the report to stdout
+func PrintReport(report *types.InventoryReport, filter util.ReportFilter, outputFormat string, opts ...PrintOpt) error {
+	return WriteReport(os.Stdout, report, filter, outputFormat, opts...)
+}
+
+// WriteReport writes the filtered and sorted report to w in an output format
+func WriteReport(w io.Writer, report *types.InventoryReport, filter util.ReportFilter, outputFormat string, opts ...PrintOpt) error {
+	options := &printOptions{soonest: util.DefaultSoonest}
+	for _, opt := range opts {
+		opt(options)
+	}
+
 	report = util.FilterReport(report, filter)
+	if report == nil {
+		return fmt.Errorf("no report was produced")
+	}
+	err := util.SortReport(report, options.sortBy)
+	if err != nil {
+		return err
+	}
+
+	if len(options.groupBy) > 0 && !isGroupable(outputFormat) {
+		return fmt.Errorf("-o %s cannot be grouped", outputFormat)
+	}
+	if isGraphFormat(outputFormat) {
+		return printGraph(w, util.ReportGraph(*report, options.collapseValid), outputFormat)
+	}
+	switch outputFormat {
+	case "html":
+		return printHTML(w, *report)
+	case "sarif":
+		return printJSON(w, util.ReportToSarif(*report))
+	case "junit":
+		return printXML(w, util.ReportToJUnit(*report))
+	case "cyclonedx":
+		return printJSON(w, util.ReportToCycloneDX(*report, time.Now()))
+	case "summary":
+		return printSummary(w, *report, options)
+	case "prometheus":
+		_, err := io.WriteString(w, util.ReportToPrometheus(*report))
+		if err != nil {
+			return fmt.Errorf("failed to write prometheus metrics: %w", err)
+		}
+		return nil
+	}
+
+	var groups []util.ResourceGroup
+	if len(options.groupBy) > 0 {
+		groups, err = util.GroupReport(*report, options.groupBy)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Grouped reports are printed with their groups in place of the resources
+	var encoded interface{} = report
+	if groups != nil {
+		encoded = util.GroupedReport{
+			Identity: report.Identity,
+			GroupBy:  options.groupBy,
+			Groups:   groups,
+			Errors:   report.Errors,
+			Stats:    report.Stats,
+		}
+	}
+
+	if spec, ok := strings.CutPrefix(outputFormat, "custom-columns="); ok {
+		options.columns, err = util.ParseColumns(spec)
+		if err != nil {
+			return err
+		}
+		outputFormat = "text"
+	}
+	if text, ok := strings.CutPrefix(outputFormat, "go-template="); ok {
+		return printTemplate(w, encoded, text)
+	}
+
 	switch outputFormat {
 	case "json":
-		b, err := json.MarshalIndent(report, "", "  ")
+		b, err := json.MarshalIndent(encoded, "", "  ")
 		if err != nil {
 			return fmt.Errorf("failed to marshal json report: %w", err)
 		}
-		writer := bufio.NewWriter(os.Stdout)
+		writer := bufio.NewWriter(w)
 		_, err = writer.WriteString(string(b))
 		if err != nil {
 			return fmt.Errorf("failed to write yaml report: %w", err)
 		}
 		writer.Flush()
+	case "markdown":
+		return printMarkdown(w, *report, groups, options)
+	case "csv":
+		return printRecords(w, *report, groups, options, ',')
+	case "tsv":
+		return printRecords(w, *report, groups, options, '\t')
 	case "yaml":
-		b, err := yaml.Marshal(report)
+		b, err := yaml.Marshal(encoded)
 		if err != nil {
 			return fmt.Errorf("failed to marshal yaml report: %w", err)
 		}
-		writer := bufio.NewWriter(os.Stdout)
+		writer := bufio.NewWriter(w)
 		_, err = writer.WriteString(string(b))
 		if err != nil {
 			return fmt.Errorf("failed to write yaml report: %w", err)
@@ -39,7 +181,7 @@ func PrintReport(report *types.InventoryReport, filter util.ReportFilter, output
 		writer.Flush()
 	default:
 		if len(report.Identity.AwsAccountNumber) > 0 {
-			writer := bufio.NewWriter(os.Stdout)
+			writer := bufio.NewWriter(w)
 			_, err := writer.WriteString(fmt.Sprintf("\n\nAccount: %s\n\n", report.Identity.AwsAccountNumber))
 			if err != nil {
 				return fmt.Errorf("failed to write yaml report: %w", err)
@@ -47,17 +189,257 @@ func PrintReport(report *types.InventoryReport, filter util.ReportFilter, output
 			writer.Flush()
 		}
 
-		table := tablewriter.NewWriter(os.Stdout)
-		table.SetHeader([]string{"Kind", "Name", "Parent", "Version", "Current", "Status", "EOL Date"})
-		table.SetAlignment(tablewriter.ALIGN_LEFT)
-		table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
-		table.SetBorder(false)
-		table.SetHeaderLine(false)
-		table.SetColumnSeparator("")
-		table.SetCenterSeparator("")
-		table.SetAutoWrapText(true)
-		table.AppendBulk(util.ReportToTable(*report))
+		if groups == nil {
+			printResourceTable(w, *report, options)
+		}
+		for _, group := range groups {
+			writer := bufio.NewWriter(w)
+			_, err := writer.WriteString(fmt.Sprintf("\n%s: %s (%s)\n\n", options.groupBy, group.Key, statusSubtotal(group)))
+			if err != nil {
+				return fmt.Errorf("failed to write group: %w", err)
+			}
+			writer.Flush()
+			printResourceTable(w, group.Report, options)
+		}
+
+		if !report.Complete() {
+			writer := bufio.NewWriter(w)
+			_, err := writer.WriteString("\n\nErrors (the report is incomplete):\n\n")
+			if err != nil {
+				return fmt.Errorf("failed to write errors: %w", err)
+			}
+			writer.Flush()
+
+			table := newTable(w, []string{"Source", "Extractor", "Account", "Region", "Resource", "Message"})
+			table.AppendBulk(util.ErrorsToTable(*report))
+			table.Render()
+		}
+	}
+	return nil
+}
+
+// fileTypes are the file extensions and media types of the output formats
+var fileTypes = map[string]struct{ extension, contentType string }{
+	"json":       {"json", "application/json"},
+	"yaml":       {"yaml", "application/yaml"},
+	"text":       {"txt", "text/plain; charset=utf-8"},
+	"markdown":   {"md", "text/markdown; charset=utf-8"},
+	"csv":        {"csv", "text/csv; charset=utf-8"},
+	"tsv":        {"tsv", "text/tab-separated-values; charset=utf-8"},
+	"html":       {"html", "text/html; charset=utf-8"},
+	"sarif":      {"sarif", "application/sarif+json"},
+	"junit":      {"xml", "application/xml"},
+	"cyclonedx":  {"cdx.json", "application/vnd.cyclonedx+json"},
+	"prometheus": {"prom", "text/plain; version=0.0.4; charset=utf-8"},
+	"summary":    {"txt", "text/plain; charset=utf-8"},
+	"dot":        {"dot", "text/vnd.graphviz"},
+	"mermaid":    {"mmd", "text/plain; charset=utf-8"},
+	"graph-json": {"json", "application/json"},
+}
+
+// FileType returns the file extension and media type of an output format. Custom columns, templates
+// and unknown formats are plain text.
+func FileType(outputFormat string) (extension string, contentType string) {
+	if t, ok := fileTypes[outputFormat]; ok {
+		return t.extension, t.contentType
+	}
+	return fileTypes["text"].extension, fileTypes["text"].contentType
+}
+
+// IsFormat tells whether a name is an output format, including custom-columns= and go-template= ones
+func IsFormat(outputFormat string) bool {
+	if _, ok := fileTypes[outputFormat]; ok {
+		return true
+	}
+	return strings.HasPrefix(outputFormat, "custom-columns=") || strings.HasPrefix(outputFormat, "go-template=")
+}
+
+// FormatOfFile tells the output format of a file from its extension, e.g. json for report.json
+func FormatOfFile(path string) (string, bool) {
+	if strings.HasSuffix(strings.ToLower(path), ".cdx.json") {
+		return "cyclonedx", true
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return "json", true
+	case ".yaml", ".yml":
+		return "yaml", true
+	case ".txt":
+		return "text", true
+	case ".md":
+		return "markdown", true
+	case ".csv":
+		return "csv", true
+	case ".tsv":
+		return "tsv", true
+	case ".html":
+		return "html", true
+	case ".sarif":
+		return "sarif", true
+	case ".xml":
+		return "junit", true
+	case ".prom":
+		return "prometheus", true
+	case ".dot":
+		return "dot", true
+	case ".mmd":
+		return "mermaid", true
+	}
+	return "", false
+}
+
+// isGroupable tells whether --group-by applies to an output format, the others have a fixed layout
+func isGroupable(outputFormat string) bool {
+	switch outputFormat {
+	case "html", "sarif", "junit", "cyclonedx", "prometheus", "summary":
+		return false
+	}
+	return !isGraphFormat(outputFormat)
+}
+
+func printXML(w io.Writer, v interface{}) error {
+	b, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal xml: %w", err)
+	}
+	_, err = w.Write(append([]byte(xml.Header), append(b, '\n')...))
+	if err != nil {
+		return fmt.Errorf("failed to write xml: %w", err)
+	}
+	return nil
+}
+
+func printResourceTable(w io.Writer, report types.InventoryReport, options *printOptions) {
+	if options.columns != nil {
+		header := []string{}
+		for _, column := range options.columns {
+			header = append(header, column.Header)
+		}
+		table := newTable(w, header)
+		table.AppendBulk(util.ColumnsToTable(report, options.columns))
 		table.Render()
+		return
+	}
+
+	header := []string{"Kind", "Name", "Parent", "Version", "Current", "Status", "EOL Date"}
+	for _, tag := range options.tagColumns {
+		header = append(header, "Tag:"+tag)
+	}
+	rows := util.ReportToTable(report, options.tagColumns...)
+	if options.upgradePath {
+		header = append(header, "Upgrade Path")
+		for i, item := range report.Resources {
+			rows[i] = append(rows[i], util.FormatUpgradePath(item.GetVersionedResource().Remediation))
+		}
+	}
+	table := newTable(w, header)
+	table.AppendBulk(rows)
+	table.Render()
+}
+
+// statusSubtotal reads e.g. "5 resources: 1 CRITICAL, 2 WARNING, 2 VALID"
+func statusSubtotal(group util.ResourceGroup) string {
+	counts := group.StatusCounts()
+	subtotal := fmt.Sprintf("%d resources", len(group.Report.Resources))
+	if len(group.Report.Resources) == 1 {
+		subtotal = "1 resource"
+	}
+	parts := []string{}
+	for _, status := range []types.Status{types.StatusCritical, types.StatusWarning, types.StatusValid} {
+		if counts[status] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[status], status))
+		}
+	}
+	if len(parts) > 0 {
+		subtotal += ": " + strings.Join(parts, ", ")
+	}
+	return subtotal
+}
+
+func newTable(w io.Writer, header []string) *tablewriter.Table {
+	table := tablewriter.NewWriter(w)
+	table.SetHeader(header)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetBorder(false)
+	table.SetHeaderLine(false)
+	table.SetColumnSeparator("")
+	table.SetCenterSeparator("")
+	table.SetAutoWrapText(true)
+	return table
+}
+
+// printTemplate executes a go template against the report, or the grouped report
+func printTemplate(w io.Writer, data interface{}, text string) error {
+	tmpl, err := template.New("output").Parse(text)
+	if err != nil {
+		return fmt.Errorf("invalid go-template: %w", err)
+	}
+	writer := bufio.NewWriter(w)
+	err = tmpl.Execute(writer, data)
+	if err != nil {
+		return fmt.Errorf("failed to execute go-template: %w", err)
+	}
+	return writer.Flush()
+}
+
+// printRecords writes a header row and one row per resource, with the group of each resource first
+// when the report is grouped
+func printRecords(w io.Writer, report types.InventoryReport, groups []util.ResourceGroup, options *printOptions, comma rune) error {
+	writer := csv.NewWriter(w)
+	writer.Comma = comma
+
+	if groups == nil {
+		header, records := util.ReportToRecords(report, options.tagColumns...)
+		records = append([][]string{header}, records...)
+		err := writer.WriteAll(records)
+		if err != nil {
+			return fmt.Errorf("failed to write records: %w", err)
+		}
+		return printErrorComments(w, report)
+	}
+
+	// The records of all the groups at once, so that every group has the same columns
+	grouped := types.InventoryReport{}
+	keys := []string{}
+	for _, group := range groups {
+		grouped.Resources = append(grouped.Resources, group.Report.Resources...)
+		for range group.Report.Resources {
+			keys = append(keys, group.Key)
+		}
+	}
+	header, records := util.ReportToRecords(grouped, options.tagColumns...)
+	err := writer.Write(append([]string{options.groupBy}, header...))
+	if err != nil {
+		return fmt.Errorf("failed to write records: %w", err)
+	}
+	for i, record := range records {
+		err = writer.Write(append([]string{keys[i]}, record...))
+		if err != nil {
+			return fmt.Errorf("failed to write records: %w", err)
+		}
+	}
+	writer.Flush()
+	err = writer.Error()
+	if err != nil {
+		return fmt.Errorf("failed to write records: %w", err)
+	}
+	return printErrorComments(w, report)
+}
+
+// printErrorComments trails csv and tsv records with the scrape errors, as comment lines readers can skip
+func printErrorComments(w io.Writer, report types.InventoryReport) error {
+	if len(report.Errors) == 0 {
+		return nil
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %s:\n", incompleteNote(len(report.Errors)))
+	for _, e := range report.Errors {
+		fmt.Fprintf(&sb, "# %s\n", util.FormatScrapeError(e))
+	}
+	_, err := io.WriteString(w, sb.String())
+	if err != nil {
+		return fmt.Errorf("failed to write errors: %w", err)
 	}
 	return nil
 }
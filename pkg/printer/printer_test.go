@@ -0,0 +1,118 @@
+package printer
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+	"github.com/chanzuckerberg/camelot/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func testReport() *types.InventoryReport {
+	account := func(id string) []types.ParentResource {
+		return []types.ParentResource{{Kind: types.KindAWSAccount, ID: id}}
+	}
+	return &types.InventoryReport{
+		Identity: types.Indentity{AwsAccountNumber: "123"},
+		Resources: []types.Versioned{
+			types.EKSCluster{VersionedResource: types.VersionedResource{Kind: types.KindEKSCluster, ID: "prod", Parents: account("123"), Version: "1.24",
+				Owner: "platform", EOL: types.EOLStatus{EOLDate: "2024-01-31", RemainingDays: 20, Status: types.StatusCritical}}},
+			types.RDSCluster{VersionedResource: types.VersionedResource{Kind: types.KindRDSCluster, ID: "analytics", Parents: account("123"), Version: "13.7",
+				Owner: "data", EOL: types.EOLStatus{EOLDate: "2025-11-13", RemainingDays: 200, Status: types.StatusValid}}},
+			types.Lambda{VersionedResource: types.VersionedResource{Kind: types.KindLambda, ID: "api", Parents: account("123"), Version: "python3.8",
+				Owner: "platform", EOL: types.EOLStatus{EOLDate: "2024-03-01", RemainingDays: 60, Status: types.StatusWarning}}},
+		},
+		Errors: []types.ScrapeError{{Source: "aws", Extractor: "lambda", Account: "123", Region: "us-east-1", Message: "access denied"}},
+		Stats:  []types.ScrapeStat{{Source: "aws", Extractor: "eks", Account: "123", Region: "us-west-2", Duration: 2}},
+	}
+}
+
+func writeReport(r *require.Assertions, report *types.InventoryReport, filters []string, outputFormat string, opts ...PrintOpt) string {
+	filter, err := util.CreateFilter(filters)
+	r.NoError(err)
+	var b bytes.Buffer
+	r.NoError(WriteReport(&b, report, filter, outputFormat, opts...))
+	return b.String()
+}
+
+func TestGroupedReport(t *testing.T) {
+	r := require.New(t)
+
+	// Grouped machine formats keep the identity, errors and stats of the report
+	grouped := util.GroupedReport{}
+	r.NoError(json.Unmarshal([]byte(writeReport(r, testReport(), nil, "json", WithGroupBy("owner"))), &grouped))
+	r.Equal("123", grouped.Identity.AwsAccountNumber)
+	r.Equal("owner", grouped.GroupBy)
+	r.Len(grouped.Errors, 1)
+	r.Equal("access denied", grouped.Errors[0].Message)
+	r.Len(grouped.Stats, 1)
+	r.Len(grouped.Groups, 2)
+	r.Equal("data", grouped.Groups[0].Key)
+	r.Equal("platform", grouped.Groups[1].Key)
+
+	yaml := writeReport(r, testReport(), nil, "yaml", WithGroupBy("owner"))
+	r.Contains(yaml, "access denied")
+	r.Contains(yaml, "key: platform")
+
+	out := writeReport(r, testReport(), nil, "go-template={{.GroupBy}}:{{range .Groups}} {{.Key}}{{end}}, {{len .Errors}} error", WithGroupBy("owner"))
+	r.Equal("owner: data platform, 1 error", out)
+
+	out = writeReport(r, testReport(), nil, "text", WithGroupBy("owner"))
+	r.Contains(out, "owner: platform (2 resources")
+	r.Contains(out, "Errors (the report is incomplete)")
+
+	filter, err := util.CreateFilter(nil)
+	r.NoError(err)
+	r.Error(WriteReport(&bytes.Buffer{}, testReport(), filter, "sarif", WithGroupBy("owner")))
+}
+
+func TestGroupedRecords(t *testing.T) {
+	r := require.New(t)
+
+	// Resources sharing an identity, like a TFC resource tracked on two branches, keep their own rows
+	report := testReport()
+	report.Resources = append(report.Resources, types.TfcResource{
+		VersionedResource: types.VersionedResource{Kind: types.KindTFCResource, ID: "eks:cluster/prod", Version: "main", Owner: "platform"},
+	}, types.TfcResource{
+		VersionedResource: types.VersionedResource{Kind: types.KindTFCResource, ID: "eks:cluster/prod", Version: "release", Owner: "platform"},
+	})
+
+	out := writeReport(r, report, nil, "csv", WithGroupBy("owner"))
+	lines := strings.Split(out, "\n")
+	r.True(strings.HasPrefix(lines[0], "owner,kind,id,"))
+	r.True(strings.HasPrefix(lines[1], "data,rds,analytics,"))
+	r.True(strings.HasPrefix(lines[2], "platform,eks,prod,"))
+	r.True(strings.HasPrefix(lines[3], "platform,lambda,api,"))
+	r.True(strings.HasPrefix(lines[4], "platform,tfc-resource,eks:cluster/prod,,,main,"))
+	r.True(strings.HasPrefix(lines[5], "platform,tfc-resource,eks:cluster/prod,,,release,"))
+}
+
+func TestErrorsInOutputs(t *testing.T) {
+	r := require.New(t)
+
+	// Records are trailed by comments, grouped or not
+	for _, opts := range [][]PrintOpt{nil, {WithGroupBy("owner")}} {
+		lines := strings.Split(strings.TrimSpace(writeReport(r, testReport(), nil, "tsv", opts...)), "\n")
+		r.Equal([]string{
+			"# 1 scrape error, the report is incomplete:",
+			"# aws/lambda/123/us-east-1: access denied",
+		}, lines[len(lines)-2:])
+	}
+	r.NotContains(writeReport(r, &types.InventoryReport{Resources: testReport().Resources}, nil, "csv"), "#")
+
+	dot := writeReport(r, testReport(), nil, "dot")
+	r.Contains(dot, "  // error: aws/lambda/123/us-east-1: access denied\n")
+	r.Contains(dot, "  label=\"1 scrape error, the report is incomplete\";\n")
+
+	mermaid := writeReport(r, testReport(), nil, "mermaid")
+	r.Contains(mermaid, "  %% error: aws/lambda/123/us-east-1: access denied\n")
+	r.Contains(mermaid, "  errors[\"1 scrape error, the report is incomplete\"]:::critical\n")
+
+	graph := util.Graph{}
+	r.NoError(json.Unmarshal([]byte(writeReport(r, testReport(), nil, "graph-json")), &graph))
+	r.Len(graph.Errors, 1)
+	r.Equal("access denied", graph.Errors[0].Message)
+}
@@ -0,0 +1,114 @@
+package printer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+	"github.com/chanzuckerberg/camelot/pkg/util"
+)
+
+// summaryStatuses are the columns of the summary tables, most severe first
+var summaryStatuses = []string{string(types.StatusCritical), string(types.StatusWarning), string(types.StatusValid)}
+
+// printSummary writes the status counts by kind and by account or org, and the resources reaching their
+// end of life the soonest, instead of a row per resource
+func printSummary(w io.Writer, report types.InventoryReport, options *printOptions) error {
+	now := time.Now()
+	summary := util.SummarizeReport(report, now, options.soonest)
+	statuses := append([]string{}, summaryStatuses...)
+	if summary.Statuses[util.NoValue] > 0 {
+		statuses = append(statuses, util.NoValue)
+	}
+
+	writer := bufio.NewWriter(w)
+	if len(report.Identity.AwsAccountNumber) > 0 {
+		fmt.Fprintf(writer, "\nAccount: %s\n", report.Identity.AwsAccountNumber)
+	}
+	fmt.Fprintf(writer, "\n%s\n", statusSubtotal(util.ResourceGroup{Report: report}))
+	if summary.Errors > 0 {
+		fmt.Fprintln(writer, incompleteNote(summary.Errors))
+	}
+	fmt.Fprint(writer, "\nBy kind:\n\n")
+	err := writer.Flush()
+	if err != nil {
+		return fmt.Errorf("failed to write summary: %w", err)
+	}
+
+	kinds := map[string]map[string]int{}
+	for kind, counts := range summary.Kinds {
+		kinds[string(kind)] = counts
+	}
+	writeSummaryTable(w, "Kind", kinds, statuses, summary.Statuses)
+
+	fmt.Fprint(writer, "\nBy account or org:\n\n")
+	err = writer.Flush()
+	if err != nil {
+		return fmt.Errorf("failed to write summary: %w", err)
+	}
+	writeSummaryTable(w, "Scope", summary.Scopes, statuses, summary.Statuses)
+
+	if len(summary.Soonest) == 0 {
+		return nil
+	}
+	fmt.Fprintf(writer, "\nPast or soonest end of life (%d):\n\n", len(summary.Soonest))
+	err = writer.Flush()
+	if err != nil {
+		return fmt.Errorf("failed to write summary: %w", err)
+	}
+	table := newTable(w, []string{"Kind", "Name", "Parent", "Version", "Status", "EOL Date", "Days"})
+	for _, resource := range summary.Soonest {
+		days := ""
+		if eolDate, err := time.Parse("2006-01-02", resource.EOL.EOLDate); err == nil {
+			days = strconv.Itoa(util.RemainingDays(eolDate))
+		}
+		table.Append([]string{
+			string(resource.Kind),
+			resource.ID,
+			util.FormatParents(resource.Parents),
+			resource.Version,
+			string(resource.EOL.Status),
+			resource.EOL.EOLDate,
+			days,
+		})
+	}
+	table.Render()
+	return nil
+}
+
+// writeSummaryTable writes a row of status counts per key, sorted by key, and a row of totals
+func writeSummaryTable(w io.Writer, header string, counts map[string]map[string]int, statuses []string, totals map[string]int) {
+	table := newTable(w, append(append([]string{header}, statuses...), "Total"))
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	row := func(key string, counts map[string]int) []string {
+		cells := []string{key}
+		total := 0
+		for _, status := range statuses {
+			cells = append(cells, strconv.Itoa(counts[status]))
+			total += counts[status]
+		}
+		return append(cells, strconv.Itoa(total))
+	}
+	for _, key := range keys {
+		table.Append(row(key, counts[key]))
+	}
+	table.Append(row("total", totals))
+	table.Render()
+}
+
+// incompleteNote tells how many scrape errors left the report incomplete
+func incompleteNote(errors int) string {
+	if errors == 1 {
+		return "1 scrape error, the report is incomplete"
+	}
+	return fmt.Sprintf("%d scrape errors, the report is incomplete", errors)
+}
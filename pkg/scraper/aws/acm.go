@@ -9,6 +9,7 @@ import (
 	acmtypes "github.com/aws/aws-sdk-go-v2/service/acm/types"
 	"github.com/chanzuckerberg/camelot/pkg/scraper/interfaces"
 	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+	"github.com/sirupsen/logrus"
 )
 
 func extractACMCertificates(ctx context.Context, awsClient interfaces.AWSClient) (*types.InventoryReport, error) {
@@ -17,6 +18,9 @@ func extractACMCertificates(ctx context.Context, awsClient interfaces.AWSClient)
 		return nil, fmt.Errorf("unable to list certificates: %w", err)
 	}
 	certificates := []types.Versioned{}
+	// Tags are optional, certificates whose tags cannot be listed are reported without labels
+	var tagErr error
+	untagged := 0
 	for _, certificate := range out {
 		parts := strings.Split(*certificate.CertificateArn, "/")
 		var status types.Status = types.StatusValid
@@ -41,16 +45,23 @@ func extractACMCertificates(ctx context.Context, awsClient interfaces.AWSClient)
 		case acmtypes.CertificateStatusValidationTimedOut:
 			status = types.StatusCritical
 		case acmtypes.CertificateStatusIssued:
-			// Imported certificates are not eligible for renewal, this one is expiring in 30 days
-			if certificate.RenewalEligibility == acmtypes.RenewalEligibilityIneligible && certificate.NotAfter.Before(time.Now().AddDate(0, 0, 30)) {
-				status = types.StatusWarning
-			} else if !*certificate.InUse {
+			// Imported certificates are not eligible for renewal, they are judged by their expiration
+			if certificate.RenewalEligibility == acmtypes.RenewalEligibilityIneligible {
+				status = eolStatus(types.KindACMCertificate, remainingDays(eol))
+			}
+			if status == types.StatusValid && !*certificate.InUse {
 				// Certificate is ussued, but not used
 				status = types.StatusWarning
 			}
 		}
 
 		daysDiff := remainingDays(eol)
+		tags, err := awsClient.ListACMCertificateTags(*certificate.CertificateArn)
+		if err != nil {
+			logrus.Debugf("unable to list tags of certificate %s: %s", parts[1], err.Error())
+			tagErr = err
+			untagged++
+		}
 		certificates = append(certificates, types.ACMCertificate{
 			InUse:            *certificate.InUse,
 			Status:           string(certificate.Status),
@@ -68,10 +79,14 @@ func extractACMCertificates(ctx context.Context, awsClient interfaces.AWSClient)
 					RemainingDays: daysDiff,
 					Status:        status,
 				},
+				Labels: tagsToLabels(tags, func(tag acmtypes.Tag) (*string, *string) {
+					return tag.Key, tag.Value
+				}),
 			},
 		})
 	}
-	return &types.InventoryReport{
-		Resources: certificates,
-	}, nil
+	if tagErr != nil {
+		logrus.Warnf("unable to list the tags of %d certificates, they have no labels: %s", untagged, tagErr.Error())
+	}
+	return &types.InventoryReport{Resources: certificates}, nil
 }
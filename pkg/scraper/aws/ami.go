@@ -81,8 +81,10 @@ func extractAMIs(ctx context.Context, awsClient interfaces.AWSClient) (*types.In
 					EOL: types.EOLStatus{
 						EOLDate:       endDate,
 						RemainingDays: daysDiff,
-						Status:        eolStatus(daysDiff),
+						Status:        eolStatus(types.KindMachineImage, daysDiff),
 					},
+					// The image is reported per instance, the instance tags tell who runs it
+					Labels: ec2TagsToLabels(instance.Tags),
 				},
 			})
 		}
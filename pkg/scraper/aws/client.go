@@ -14,8 +14,10 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/eks"
 	"github.com/aws/aws-sdk-go-v2/service/lambda"
 	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
 	"github.com/aws/aws-sdk-go-v2/service/sts"
 	"github.com/chanzuckerberg/camelot/pkg/scraper/interfaces"
+	"github.com/chanzuckerberg/camelot/pkg/util"
 	"github.com/sirupsen/logrus"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/rest"
@@ -179,6 +181,15 @@ func (a *awsClient) ListLambdaFunctions() (*lambda.ListFunctionsOutput, error) {
 	return out, nil
 }
 
+func (a *awsClient) ListLambdaTags(arn string) (map[string]string, error) {
+	client := lambda.NewFromConfig(*a.cfg)
+	out, err := client.ListTags(a.ctx, &lambda.ListTagsInput{Resource: aws.String(arn)})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list function tags: %w", err)
+	}
+	return out.Tags, nil
+}
+
 func (a *awsClient) DescribeRDSClusters() (*rds.DescribeDBClustersOutput, error) {
 	client := rds.NewFromConfig(*a.cfg)
 
@@ -189,6 +200,25 @@ func (a *awsClient) DescribeRDSClusters() (*rds.DescribeDBClustersOutput, error)
 	return out, nil
 }
 
+// DescribeRDSEngineVersions lists every version of an engine, with the versions each can be upgraded to
+func (a *awsClient) DescribeRDSEngineVersions(engine string) ([]rdstypes.DBEngineVersion, error) {
+	client := rds.NewFromConfig(*a.cfg)
+
+	versions := []rdstypes.DBEngineVersion{}
+	paginator := rds.NewDescribeDBEngineVersionsPaginator(client, &rds.DescribeDBEngineVersionsInput{
+		Engine:     aws.String(engine),
+		IncludeAll: aws.Bool(true),
+	})
+	for paginator.HasMorePages() {
+		out, err := paginator.NextPage(a.ctx)
+		if err != nil {
+			return nil, fmt.Errorf("unable to list %s engine versions: %w", engine, err)
+		}
+		versions = append(versions, out.DBEngineVersions...)
+	}
+	return versions, nil
+}
+
 func (a *awsClient) ListEC2Instances() ([]types.Instance, error) {
 	instances := []types.Instance{}
 	client := ec2.NewFromConfig(*a.cfg)
@@ -288,8 +318,17 @@ func (a *awsClient) ListACMCertificates() ([]acmtypes.CertificateSummary, error)
 	return certificates, nil
 }
 
+func (a *awsClient) ListACMCertificateTags(arn string) ([]acmtypes.Tag, error) {
+	client := acm.NewFromConfig(*a.cfg)
+	out, err := client.ListTagsForCertificate(a.ctx, &acm.ListTagsForCertificateInput{CertificateArn: aws.String(arn)})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list certificate tags: %w", err)
+	}
+	return out.Tags, nil
+}
+
 func getAwsConfig(ctx context.Context, profile, region, roleARN string) (*aws.Config, error) {
-	opts := []func(*config.LoadOptions) error{}
+	opts := []func(*config.LoadOptions) error{config.WithHTTPClient(util.HTTPClient())}
 	if len(profile) > 0 {
 		opts = append(opts, config.WithSharedConfigProfile(profile))
 	}
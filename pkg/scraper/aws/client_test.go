@@ -7,7 +7,10 @@ import (
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/pem"
+	"errors"
 	"math/big"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -20,6 +23,7 @@ import (
 	rds_types "github.com/aws/aws-sdk-go-v2/service/rds/types"
 	mock_interfaces "github.com/chanzuckerberg/camelot/mocks/mock_aws"
 	scraper_types "github.com/chanzuckerberg/camelot/pkg/scraper/types"
+	"github.com/chanzuckerberg/camelot/pkg/util"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 )
@@ -98,6 +102,33 @@ func TestListEKSClusters(t *testing.T) {
 	}
 }
 
+func TestEKSClusterErrors(t *testing.T) {
+	r := require.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte(`[{"cycle":"1.28","eol":"2024-11-26"}]`))
+	}))
+	defer server.Close()
+	util.SetEndpoints(util.Endpoints{EndOfLife: server.URL})
+	defer util.SetEndpoints(util.DefaultEndpoints)
+	ResetCaches()
+	defer ResetCaches()
+
+	ctrl := gomock.NewController(t)
+	mockClient := mock_interfaces.NewMockAWSClient(ctrl)
+	mockClient.EXPECT().GetAccountId().Return("123456789012").AnyTimes()
+	mockClient.EXPECT().GetEKSClusters().Return([]string{"cluster1"}, nil)
+	mockClient.EXPECT().DescribeEKSCluster("cluster1").Return(nil, errors.New("access denied"))
+
+	report, err := extractEksClusterInfo(context.Background(), mockClient)
+	r.NoError(err)
+	r.Empty(report.Resources)
+	r.Len(report.Errors, 1)
+	r.Equal(scraper_types.KindEKSCluster, report.Errors[0].Resource.Kind)
+	r.Equal("cluster1", report.Errors[0].Resource.ID)
+	r.False(report.Complete())
+}
+
 func generateCert(san []string, orgName string) ([]byte, error) {
 	priv, err := rsa.GenerateKey(rand.Reader, 2048)
 	if err != nil {
@@ -213,3 +244,54 @@ func TestListCertificates(t *testing.T) {
 	r.NoError(err)
 	r.NotEmpty(vols)
 }
+
+func TestResourceLabels(t *testing.T) {
+	r := require.New(t)
+
+	ctrl := gomock.NewController(t)
+	mockClient := mock_interfaces.NewMockAWSClient(ctrl)
+	mockClient.EXPECT().GetAccountId().Return("123456789012").AnyTimes()
+	mockClient.EXPECT().ListVolumes().Return([]ec2types.Volume{
+		{
+			VolumeId: aws.String("vol-1"),
+			Size:     aws.Int32(10),
+			Tags:     []ec2types.Tag{{Key: aws.String("team"), Value: aws.String("payments")}},
+		},
+		{
+			VolumeId: aws.String("vol-2"),
+			Size:     aws.Int32(10),
+		},
+	}, nil)
+
+	report, err := extractVolumes(context.Background(), mockClient)
+	r.NoError(err)
+	r.Len(report.Resources, 2)
+	r.Equal(map[string]string{"team": "payments"}, report.Resources[0].GetVersionedResource().Labels)
+	r.Nil(report.Resources[1].GetVersionedResource().Labels)
+
+	arn := "arn:aws:acm:us-west-2:123456789012:certificate/12345678-1234-1234-1234-123456789012"
+	mockClient.EXPECT().ListACMCertificates().Return([]acmtypes.CertificateSummary{
+		{
+			CertificateArn: aws.String(arn),
+			DomainName:     aws.String("example.com"),
+			InUse:          aws.Bool(true),
+			Status:         acmtypes.CertificateStatusIssued,
+		},
+		{
+			CertificateArn: aws.String(arn + "0"),
+			DomainName:     aws.String("example.org"),
+			InUse:          aws.Bool(true),
+			Status:         acmtypes.CertificateStatusIssued,
+		},
+	}, nil)
+	mockClient.EXPECT().ListACMCertificateTags(arn).Return([]acmtypes.Tag{{Key: aws.String("owner"), Value: aws.String("infra")}}, nil)
+	mockClient.EXPECT().ListACMCertificateTags(arn+"0").Return(nil, errors.New("access denied"))
+
+	report, err = extractACMCertificates(context.Background(), mockClient)
+	r.NoError(err)
+	r.Len(report.Resources, 2)
+	r.Equal(map[string]string{"owner": "infra"}, report.Resources[0].GetVersionedResource().Labels)
+	// Tags which cannot be listed leave the report complete
+	r.Nil(report.Resources[1].GetVersionedResource().Labels)
+	r.True(report.Complete())
+}
@@ -48,9 +48,15 @@ func extractEksClusterInfo(ctx context.Context, awsClient interfaces.AWSClient)
 	for i, cluster := range clusters {
 		go func(cluster string, i int) {
 			defer wg.Done()
-			report, err := processCluster(ctx, awsClient, cluster, cycleMap, activeVersion)
+			report, err := processCluster(ctx, awsClient, cluster, *cycles, cycleMap, activeVersion)
 			if err != nil {
 				logrus.Debugf("error processing cluster %s: %s", cluster, err.Error())
+				reports[i] = &types.InventoryReport{
+					Errors: []types.ScrapeError{{
+						Resource: &types.ParentResource{Kind: types.KindEKSCluster, ID: cluster},
+						Message:  err.Error(),
+					}},
+				}
 				return
 			}
 			reports[i] = report
@@ -62,7 +68,7 @@ func extractEksClusterInfo(ctx context.Context, awsClient interfaces.AWSClient)
 	return &summary, nil
 }
 
-func processCluster(ctx context.Context, awsClient interfaces.AWSClient, cluster string, cycleMap map[string]types.ProductCycle, activeVersion string) (*types.InventoryReport, error) {
+func processCluster(ctx context.Context, awsClient interfaces.AWSClient, cluster string, cycles []types.ProductCycle, cycleMap map[string]types.ProductCycle, activeVersion string) (*types.InventoryReport, error) {
 	clusterInfo, err := awsClient.DescribeEKSCluster(cluster)
 	if err != nil {
 		return nil, fmt.Errorf("unable to describe cluster")
@@ -78,19 +84,20 @@ func processCluster(ctx context.Context, awsClient interfaces.AWSClient, cluster
 		return nil, fmt.Errorf("unable to get k8s namespaces")
 	}
 
-	helmReleases, err := getHelmReleases(ctx, config, namespaces, cluster)
+	parents := []types.ParentResource{{Kind: types.KindAWSAccount, ID: awsClient.GetAccountId()}, {Kind: types.KindEKSCluster, ID: cluster}}
+	helmReleases, err := getHelmReleases(ctx, config, namespaces, parents)
 	if err != nil {
 		return nil, fmt.Errorf("unable to get helm releases")
 	}
 
-	eol := ""
-	if cycle, ok := cycleMap[*clusterInfo.Cluster.Version]; ok {
-		eol = fmt.Sprintf("%v", cycle.EOL)
+	var cycle *types.ProductCycle
+	if c, ok := cycleMap[*clusterInfo.Cluster.Version]; ok {
+		cycle = &c
 	}
 
-	daysDiff := remainingDays(eol)
+	eol := cycleEOLStatus(types.KindEKSCluster, cycle)
 
-	logrus.Debugf("eks cluster: %s -> %s: [%d]", *clusterInfo.Cluster.Arn, *clusterInfo.Cluster.Version, daysDiff)
+	logrus.Debugf("eks cluster: %s -> %s: [%d]", *clusterInfo.Cluster.Arn, *clusterInfo.Cluster.Version, eol.RemainingDays)
 	addons, err := awsClient.ListEKSAddons(cluster)
 	if err != nil {
 		return nil, fmt.Errorf("unable to describe addons")
@@ -119,11 +126,9 @@ func processCluster(ctx context.Context, awsClient interfaces.AWSClient, cluster
 			Parents:        []types.ParentResource{{Kind: types.KindAWSAccount, ID: awsClient.GetAccountId()}},
 			Version:        *clusterInfo.Cluster.Version,
 			CurrentVersion: activeVersion,
-			EOL: types.EOLStatus{
-				EOLDate:       eol,
-				RemainingDays: daysDiff,
-				Status:        eolStatus(daysDiff),
-			},
+			EOL:            eol,
+			Labels:         clusterInfo.Cluster.Tags,
+			Remediation:    remediation(cycles, cycle, eksUpgrades, nil),
 		},
 		PlatformVersion: *clusterInfo.Cluster.PlatformVersion,
 		Addons:          eksAddons,
@@ -7,11 +7,57 @@ import (
 	"net/http"
 
 	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+	"github.com/chanzuckerberg/camelot/pkg/util"
+	cmap "github.com/orcaman/concurrent-map/v2"
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 )
 
+// endOfLifeResult is the endoflife.date data of a product, or the error fetching it
+type endOfLifeResult struct {
+	cycles *[]types.ProductCycle
+	err    error
+}
+
+// endOfLifeCache keeps the endoflife.date data of the products for the duration of a scrape, e.g. for
+// the in-house charts of every release, which are not found there
+var endOfLifeCache = cmap.New[endOfLifeResult]()
+
+// ResetCaches forgets the end of life data and chart versions fetched so far, so that a long-running
+// process sees the latest ones on its next scrape
+func ResetCaches() {
+	endOfLifeCache.Clear()
+	artifacthubCache.Clear()
+}
+
+// Returns the release cycles of a product, newest first. User-supplied lifecycle definitions
+// are merged on top of the endoflife.date data and take precedence over it.
 func endOfLife(entity string) (*[]types.ProductCycle, error) {
-	res, err := http.Get(fmt.Sprintf("https://endoflife.date/api/%s.json", entity))
+	overrides, hasOverrides := lifecycleDefinitions.Get(entity)
+
+	result, ok := endOfLifeCache.Get(entity)
+	if !ok {
+		result.cycles, result.err = fetchEndOfLife(entity)
+		endOfLifeCache.Set(entity, result)
+	}
+	productCycles, err := result.cycles, result.err
+	if err != nil {
+		if !hasOverrides {
+			return nil, err
+		}
+		logrus.Debugf("using lifecycle definitions only for %s: %s", entity, err.Error())
+		productCycles = &[]types.ProductCycle{}
+	}
+
+	if hasOverrides {
+		merged := mergeProductCycles(*productCycles, overrides)
+		productCycles = &merged
+	}
+	return productCycles, nil
+}
+
+func fetchEndOfLife(entity string) (*[]types.ProductCycle, error) {
+	res, err := util.HTTPClient().Get(fmt.Sprintf("%s/%s.json", util.GetEndpoints().EndOfLife, entity))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get end of life data")
 	}
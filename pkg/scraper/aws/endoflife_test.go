@@ -1,8 +1,11 @@
 package aws
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	"github.com/chanzuckerberg/camelot/pkg/util"
 	"github.com/stretchr/testify/require"
 )
 
@@ -16,3 +19,30 @@ func TestVersion(t *testing.T) {
 		r.NotEmpty(cycles)
 	}
 }
+
+func TestEndOfLifeMirror(t *testing.T) {
+	r := require.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/api/amazon-eks.json" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`[{"cycle":"1.28","eol":"2024-11-26","latest":"1.28"},{"cycle":"1.27","eol":"2024-07-24"}]`))
+	}))
+	defer server.Close()
+
+	util.SetEndpoints(util.Endpoints{EndOfLife: server.URL + "/api"})
+	defer util.SetEndpoints(util.DefaultEndpoints)
+	ResetCaches()
+	defer ResetCaches()
+
+	cycles, err := endOfLife("amazon-eks")
+	r.NoError(err)
+	r.Len(*cycles, 2)
+	r.Equal("1.28", (*cycles)[0].Cycle)
+	r.Equal("2024-07-24", (*cycles)[1].EOL)
+
+	_, err = endOfLife("unknown-product")
+	r.Error(err)
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+	"github.com/chanzuckerberg/camelot/pkg/util"
 	cmap "github.com/orcaman/concurrent-map/v2"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -18,7 +19,9 @@ import (
 
 var artifacthubCache = cmap.New[ArtifactHubSearchResults]()
 
-func getHelmReleases(ctx context.Context, config *rest.Config, namespaces []string, clusterName string) ([]types.Versioned, error) {
+// getHelmReleases lists the releases of a cluster, parents is the chain of the cluster: its account then
+// the cluster itself, so that same-named clusters of different accounts keep their own releases
+func getHelmReleases(ctx context.Context, config *rest.Config, namespaces []string, parents []types.ParentResource) ([]types.Versioned, error) {
 	helmReleases := []types.Versioned{}
 
 	for _, namespace := range namespaces {
@@ -33,6 +36,28 @@ func getHelmReleases(ctx context.Context, config *rest.Config, namespaces []stri
 			continue
 		}
 		for _, release := range releases {
+			// In-house charts are judged by the user-supplied lifecycle definitions, if there are any
+			if lifecycleDefinitions.Has(release.Chart.Metadata.Name) {
+				chartVersion := strings.TrimPrefix(release.Chart.Metadata.Version, "v")
+				currentVersion, eol, err := chartLifecycle(release.Chart.Metadata.Name, chartVersion)
+				if err != nil {
+					logrus.Debugf("unable to get %s end of life data: %s", release.Chart.Metadata.Name, err.Error())
+					continue
+				}
+				helmReleases = append(helmReleases, types.HelmRelease{
+					VersionedResource: types.VersionedResource{
+						ID:             fmt.Sprintf("%s/%s", namespace, release.Name),
+						Kind:           types.KindHelmRelease,
+						Parents:        parents,
+						Version:        chartVersion,
+						CurrentVersion: currentVersion,
+						EOL:            eol,
+					},
+					Chart: release.Chart.Metadata.Name,
+				})
+				continue
+			}
+
 			if len(release.Chart.Metadata.Home) == 0 {
 				continue
 			}
@@ -90,7 +115,7 @@ func getHelmReleases(ctx context.Context, config *rest.Config, namespaces []stri
 					ID:             fmt.Sprintf("%s/%s", namespace, release.Name),
 					Kind:           types.KindHelmRelease,
 					Arn:            "",
-					Parents:        []types.ParentResource{{Kind: types.KindEKSCluster, ID: clusterName}},
+					Parents:        parents,
 					Version:        activeVersion.String(),
 					CurrentVersion: currentVersionStr,
 					EOL: types.EOLStatus{
@@ -99,12 +124,39 @@ func getHelmReleases(ctx context.Context, config *rest.Config, namespaces []stri
 						Status:        status,
 					},
 				},
+				Chart: release.Chart.Metadata.Name,
 			})
 		}
 	}
 	return helmReleases, nil
 }
 
+// chartLifecycle returns the newest version of a chart with lifecycle definitions and the EOL status of
+// the cycle its version belongs to, from the same merged data as the other products. Versions in none
+// of the cycles are WARNING.
+func chartLifecycle(chart, version string) (string, types.EOLStatus, error) {
+	cycles, err := endOfLife(chart)
+	if err != nil {
+		return "", types.EOLStatus{}, err
+	}
+
+	currentVersion := ""
+	if len(*cycles) > 0 {
+		currentVersion = (*cycles)[0].Latest
+		if len(currentVersion) == 0 {
+			currentVersion = (*cycles)[0].Cycle
+		}
+	}
+	cycle, ok := findCycle(*cycles, version)
+	if !ok {
+		// A version the definitions do not cover cannot be told healthy
+		eol := cycleEOLStatus(types.KindHelmRelease, nil)
+		eol.Status = types.StatusWarning
+		return currentVersion, eol, nil
+	}
+	return currentVersion, cycleEOLStatus(types.KindHelmRelease, &cycle), nil
+}
+
 func parseChartVersion(version string) *semver.Version {
 	return semver.MustParse(strings.Replace(version, "v", "", 1))
 }
@@ -166,7 +218,7 @@ func findHelmChartsByName(chartName string) ([]ArtifactHubPackage, error) {
 	query.Add("deprecated", "false")
 	query.Add("sort", "stars")
 
-	p, err := url.Parse("https://artifacthub.io/api/v1/packages/search")
+	p, err := url.Parse(util.GetEndpoints().ArtifactHub + "/packages/search")
 	if err != nil {
 		return nil, fmt.Errorf("unable to parse endpoint url")
 	}
@@ -178,7 +230,7 @@ func findHelmChartsByName(chartName string) ([]ArtifactHubPackage, error) {
 		return nil, fmt.Errorf("http Get call to the artifacthub api failed")
 	}
 
-	res, err := http.DefaultClient.Do(req)
+	res, err := util.HTTPClient().Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -1,8 +1,12 @@
 package aws
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+	"github.com/chanzuckerberg/camelot/pkg/util"
 	"github.com/stretchr/testify/require"
 )
 
@@ -29,3 +33,88 @@ func TestAliasedUrls(t *testing.T) {
 	r.True(aliasedUrls("https://github.com/aws/eks-charts", "https://aws.github.io/eks-charts"))
 	r.True(aliasedUrls("https://github.com/kubernetes-sigs/metrics-server/", "https://kubernetes-sigs.github.io/metrics-server"))
 }
+
+func TestChartLifecycle(t *testing.T) {
+	r := require.New(t)
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requests++
+		if req.URL.Path != "/public-chart.json" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`[{"cycle":"3","eol":"2099-01-01","latest":"3.2.0"},{"cycle":"2","eol":"2099-01-01"}]`))
+	}))
+	defer server.Close()
+	util.SetEndpoints(util.Endpoints{EndOfLife: server.URL})
+	defer util.SetEndpoints(util.DefaultEndpoints)
+	ResetCaches()
+	defer ResetCaches()
+
+	lifecycleDefinitions.Set("internal-chart", []types.ProductCycle{
+		{Cycle: "2.1", EOL: "2099-01-01", Latest: "2.1.4"},
+		{Cycle: "2.0", EOL: true},
+	})
+	lifecycleDefinitions.Set("public-chart", []types.ProductCycle{{Cycle: "2", EOL: true}})
+	defer lifecycleDefinitions.Remove("internal-chart")
+	defer lifecycleDefinitions.Remove("public-chart")
+
+	// Charts missing from endoflife.date only have their definitions
+	currentVersion, eol, err := chartLifecycle("internal-chart", "2.0.3")
+	r.NoError(err)
+	r.Equal("2.1.4", currentVersion)
+	r.Equal(types.StatusCritical, string(eol.Status))
+	r.Equal("true", eol.EOLDate)
+
+	_, eol, err = chartLifecycle("internal-chart", "2.1.0")
+	r.NoError(err)
+	r.Equal(types.StatusValid, string(eol.Status))
+	r.Equal("2099-01-01", eol.EOLDate)
+
+	// The definitions are merged on top of endoflife.date data, like for any other product
+	currentVersion, eol, err = chartLifecycle("public-chart", "2.5.0")
+	r.NoError(err)
+	r.Equal("3.2.0", currentVersion)
+	r.Equal(types.StatusCritical, string(eol.Status))
+
+	_, eol, err = chartLifecycle("public-chart", "3.1.0")
+	r.NoError(err)
+	r.Equal(types.StatusValid, string(eol.Status))
+
+	// Versions the definitions do not cover are not healthy
+	_, eol, err = chartLifecycle("internal-chart", "1.9.0")
+	r.NoError(err)
+	r.Equal(types.StatusWarning, string(eol.Status))
+
+	// endoflife.date is asked once per product during a scrape, even for charts it does not know
+	r.Equal(2, requests)
+}
+
+func TestResetCaches(t *testing.T) {
+	r := require.New(t)
+
+	latest := "1.2.3"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte(`{"packages":[{"normalized_name":"cached-chart","version":"` + latest + `"}]}`))
+	}))
+	defer server.Close()
+	util.SetEndpoints(util.Endpoints{ArtifactHub: server.URL})
+	defer util.SetEndpoints(util.DefaultEndpoints)
+	ResetCaches()
+	defer ResetCaches()
+
+	packages, err := findHelmChartsByName("cached-chart")
+	r.NoError(err)
+	r.Equal("1.2.3", packages[0].Version)
+
+	// A scrape sees the versions fetched by the previous ones until the caches are reset
+	latest = "1.3.0"
+	packages, err = findHelmChartsByName("cached-chart")
+	r.NoError(err)
+	r.Equal("1.2.3", packages[0].Version)
+	ResetCaches()
+	packages, err = findHelmChartsByName("cached-chart")
+	r.NoError(err)
+	r.Equal("1.3.0", packages[0].Version)
+}
@@ -14,6 +14,8 @@ func extractLambdas(ctx context.Context, awsClient interfaces.AWSClient) (*types
 
 	cycleMap := map[string]types.ProductCycle{}
 	currentCycleMap := map[string]string{}
+	productCycles := map[string][]types.ProductCycle{}
+	runtimeProducts := map[string]string{}
 	products := []string{"python", "ruby", "nodejs"}
 
 	// TODO: Figure out what to do with the go1.x runtime
@@ -24,7 +26,10 @@ func extractLambdas(ctx context.Context, awsClient interfaces.AWSClient) (*types
 			return nil, fmt.Errorf("unable to get %s end of life data", product)
 		}
 
+		productCycles[product] = *cycles
 		for _, cycle := range *cycles {
+			runtimeProducts[product+cycle.Cycle] = product
+			runtimeProducts[product+cycle.Cycle+".x"] = product
 			currentCycleMap[product+cycle.Cycle] = product + (*cycles)[0].Cycle
 			currentCycleMap[product+cycle.Cycle+".x"] = product + (*cycles)[0].Cycle
 
@@ -34,24 +39,36 @@ func extractLambdas(ctx context.Context, awsClient interfaces.AWSClient) (*types
 	}
 
 	lambdas := []types.Versioned{}
+	// Tags are optional, functions whose tags cannot be listed are reported without labels
+	var tagErr error
+	untagged := 0
 	out, err := awsClient.ListLambdaFunctions()
 	if err != nil {
 		logrus.Errorf("unable to list functions: %s", err.Error())
 		return nil, err
 	}
 	for _, function := range out.Functions {
-		eol := ""
-		if cycle, ok := cycleMap[string(function.Runtime)]; ok {
-			eol = fmt.Sprintf("%v", cycle.EOL)
+		var cycle *types.ProductCycle
+		if c, ok := cycleMap[string(function.Runtime)]; ok {
+			cycle = &c
 		}
 
-		daysDiff := remainingDays(eol)
+		eol := cycleEOLStatus(types.KindLambda, cycle)
 		version := string(function.Runtime)
+		var remedy *types.Remediation
 		if function.PackageType == lambda_types.PackageTypeImage {
 			version = "unversioned"
+		} else if product, ok := runtimeProducts[version]; ok {
+			remedy = remediation(productCycles[product], cycle, lambdaUpgrades(product, version), lambdaRuntimes)
 		}
 
-		logrus.Debugf("lambda function: %s -> %s [%d]", *function.FunctionArn, function.Runtime, daysDiff)
+		logrus.Debugf("lambda function: %s -> %s [%d]", *function.FunctionArn, function.Runtime, eol.RemainingDays)
+		labels, err := awsClient.ListLambdaTags(*function.FunctionArn)
+		if err != nil {
+			logrus.Debugf("unable to list tags of function %s: %s", *function.FunctionName, err.Error())
+			tagErr = err
+			untagged++
+		}
 		lambdas = append(lambdas, types.Lambda{
 			VersionedResource: types.VersionedResource{
 				ID:             *function.FunctionName,
@@ -60,14 +77,15 @@ func extractLambdas(ctx context.Context, awsClient interfaces.AWSClient) (*types
 				Arn:            *function.FunctionArn,
 				Version:        version,
 				CurrentVersion: currentCycleMap[string(function.Runtime)],
-				EOL: types.EOLStatus{
-					EOLDate:       eol,
-					RemainingDays: daysDiff,
-					Status:        eolStatus(daysDiff),
-				},
+				EOL:            eol,
+				Labels:         labels,
+				Remediation:    remedy,
 			},
 			Engine: string(function.Runtime),
 		})
 	}
+	if tagErr != nil {
+		logrus.Warnf("unable to list the tags of %d functions, they have no labels: %s", untagged, tagErr.Error())
+	}
 	return &types.InventoryReport{Resources: lambdas}, nil
 }
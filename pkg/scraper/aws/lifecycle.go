@@ -0,0 +1,108 @@
+package aws
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+	cmap "github.com/orcaman/concurrent-map/v2"
+	"gopkg.in/yaml.v2"
+)
+
+var lifecycleDefinitions = cmap.New[[]types.ProductCycle]()
+
+// LoadLifecycleFile reads user-supplied product lifecycle definitions from a YAML (or JSON) file.
+// The file maps a product name to its release cycles, newest first, in the same shape as the
+// endoflife.date API:
+//
+//	amazon-eks:
+//	  - cycle: "1.24"
+//	    eol: 2024-03-31
+//	internal-base-chart:
+//	  - cycle: "2.1"
+//	    releaseDate: 2023-06-01
+//	    eol: 2024-06-01
+//	    latest: 2.1.4
+//
+// Products which are also tracked by endoflife.date are merged with it, and the user-supplied
+// cycles win. Helm releases are matched against products named after their chart.
+func LoadLifecycleFile(path string) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("unable to read lifecycle file %s: %w", path, err)
+	}
+
+	products := map[string][]types.ProductCycle{}
+	err = yaml.Unmarshal(b, &products)
+	if err != nil {
+		return fmt.Errorf("unable to parse lifecycle file %s: %w", path, err)
+	}
+
+	for product, cycles := range products {
+		for i, cycle := range cycles {
+			if len(cycle.Cycle) == 0 {
+				return fmt.Errorf("lifecycle file %s: product %s has a cycle without a name (entry %d)", path, product, i)
+			}
+		}
+		lifecycleDefinitions.Set(product, cycles)
+	}
+	return nil
+}
+
+// Overrides replace upstream cycles with the same name; new cycles are inserted ahead of the first
+// cycle released before them, or at the top (in file order) when no release date is known.
+func mergeProductCycles(upstream, overrides []types.ProductCycle) []types.ProductCycle {
+	merged := make([]types.ProductCycle, len(upstream))
+	copy(merged, upstream)
+
+	top := 0
+	for _, override := range overrides {
+		replaced := false
+		for i, cycle := range merged {
+			if cycle.Cycle == override.Cycle {
+				merged[i] = override
+				replaced = true
+				break
+			}
+		}
+		if replaced {
+			continue
+		}
+
+		position := top
+		if len(override.ReleaseDate) == 0 {
+			top++
+		} else {
+			position = len(merged)
+			for i, cycle := range merged {
+				if len(cycle.ReleaseDate) > 0 && cycle.ReleaseDate < override.ReleaseDate {
+					position = i
+					break
+				}
+			}
+		}
+		merged = append(merged[:position], append([]types.ProductCycle{override}, merged[position:]...)...)
+	}
+	return merged
+}
+
+// Finds the cycle a version belongs to, trying the full version first, then major.minor and major
+func findCycle(cycles []types.ProductCycle, version string) (types.ProductCycle, bool) {
+	version = strings.TrimPrefix(version, "v")
+	segments := strings.Split(version, ".")
+	candidates := []string{version}
+	if len(segments) > 1 {
+		candidates = append(candidates, segments[0]+"."+segments[1])
+	}
+	candidates = append(candidates, segments[0])
+
+	for _, candidate := range candidates {
+		for _, cycle := range cycles {
+			if cycle.Cycle == candidate {
+				return cycle, true
+			}
+		}
+	}
+	return types.ProductCycle{}, false
+}
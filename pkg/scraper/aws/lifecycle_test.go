@@ -0,0 +1,86 @@
+package aws
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadLifecycleFile(t *testing.T) {
+	r := require.New(t)
+
+	path := filepath.Join(t.TempDir(), "lifecycle.yaml")
+	err := os.WriteFile(path, []byte(`
+internal-base-chart:
+  - cycle: "2.1"
+    releaseDate: 2023-06-01
+    eol: 2024-06-01
+    latest: 2.1.4
+  - cycle: "2.0"
+    eol: true
+`), 0644)
+	r.NoError(err)
+
+	err = LoadLifecycleFile(path)
+	r.NoError(err)
+
+	cycles, ok := lifecycleDefinitions.Get("internal-base-chart")
+	r.True(ok)
+	r.Len(cycles, 2)
+	r.Equal("2024-06-01", cycles[0].EOL)
+	r.Equal("2.1.4", cycles[0].Latest)
+	r.Equal(true, cycles[1].EOL)
+
+	cycle, ok := findCycle(cycles, "v2.1.3")
+	r.True(ok)
+	r.Equal("2.1", cycle.Cycle)
+
+	_, ok = findCycle(cycles, "3.0.0")
+	r.False(ok)
+}
+
+func TestLoadLifecycleFileInvalid(t *testing.T) {
+	r := require.New(t)
+
+	path := filepath.Join(t.TempDir(), "lifecycle.yaml")
+	err := os.WriteFile(path, []byte("product:\n  - eol: 2024-06-01\n"), 0644)
+	r.NoError(err)
+	r.Error(LoadLifecycleFile(path))
+
+	r.Error(LoadLifecycleFile(filepath.Join(t.TempDir(), "missing.yaml")))
+}
+
+func TestMergeProductCycles(t *testing.T) {
+	r := require.New(t)
+
+	upstream := []types.ProductCycle{
+		{Cycle: "1.28", ReleaseDate: "2023-09-26", EOL: "2024-11-26"},
+		{Cycle: "1.27", ReleaseDate: "2023-05-24", EOL: "2024-07-24"},
+		{Cycle: "1.26", ReleaseDate: "2023-04-11", EOL: "2024-06-11"},
+	}
+	overrides := []types.ProductCycle{
+		{Cycle: "1.27", ReleaseDate: "2023-05-24", EOL: "2025-07-24"},
+		{Cycle: "1.29", ReleaseDate: "2024-01-23", EOL: "2025-03-23"},
+		{Cycle: "1.26-extended", ReleaseDate: "2023-04-12", EOL: "2025-06-11"},
+	}
+
+	merged := mergeProductCycles(upstream, overrides)
+	r.Len(merged, 5)
+	r.Equal("1.29", merged[0].Cycle)
+	r.Equal("1.28", merged[1].Cycle)
+	r.Equal("1.27", merged[2].Cycle)
+	r.Equal("2025-07-24", merged[2].EOL)
+	r.Equal("1.26-extended", merged[3].Cycle)
+	r.Equal("1.26", merged[4].Cycle)
+
+	// Upstream data is left untouched
+	r.Equal("2024-07-24", upstream[1].EOL)
+
+	merged = mergeProductCycles(nil, []types.ProductCycle{{Cycle: "2.0"}, {Cycle: "1.0"}})
+	r.Len(merged, 2)
+	r.Equal("2.0", merged[0].Cycle)
+	r.Equal("1.0", merged[1].Cycle)
+}
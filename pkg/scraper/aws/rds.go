@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
 	"github.com/chanzuckerberg/camelot/pkg/scraper/interfaces"
 	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
 	"github.com/sirupsen/logrus"
@@ -30,6 +31,22 @@ func extractRds(ctx context.Context, awsClient interfaces.AWSClient) (*types.Inv
 		}
 	}
 
+	// The versions of each engine, which tell the upgrade paths of the clusters
+	engineVersions := map[string][]rdstypes.DBEngineVersion{}
+	engineRemediation := func(engine string, cycle *types.ProductCycle, engineVersion string) *types.Remediation {
+		versions, ok := engineVersions[engine]
+		if !ok {
+			var err error
+			versions, err = awsClient.DescribeRDSEngineVersions(engine)
+			if err != nil {
+				logrus.Warnf("unable to list the %s engine versions, clusters will not get remediations: %s", engine, err.Error())
+				versions = []rdstypes.DBEngineVersion{}
+			}
+			engineVersions[engine] = versions
+		}
+		return rdsRemediation(versions, cycle, engineVersion)
+	}
+
 	rdsClusters := []types.Versioned{}
 
 	out, err := awsClient.DescribeRDSClusters()
@@ -40,25 +57,29 @@ func extractRds(ctx context.Context, awsClient interfaces.AWSClient) (*types.Inv
 	for _, instance := range out.DBClusters {
 		segments := strings.Split(*instance.EngineVersion, ".")
 
-		eol := ""
+		var cycle *types.ProductCycle
 
 		if len(segments) > 0 {
-			if cycle, ok := cycleMap[fmt.Sprintf("%s-%s", *instance.Engine, segments[0])]; ok {
-				eol = fmt.Sprintf("%v", cycle.EOL)
+			if c, ok := cycleMap[fmt.Sprintf("%s-%s", *instance.Engine, segments[0])]; ok {
+				cycle = &c
 			}
 		}
 		if len(segments) > 1 {
-			if cycle, ok := cycleMap[fmt.Sprintf("%s-%s.%s", *instance.Engine, segments[0], segments[1])]; ok {
-				eol = fmt.Sprintf("%v", cycle.EOL)
+			if c, ok := cycleMap[fmt.Sprintf("%s-%s.%s", *instance.Engine, segments[0], segments[1])]; ok {
+				cycle = &c
 			}
 		}
-		if cycle, ok := cycleMap[fmt.Sprintf("%s-%s", *instance.Engine, *instance.EngineVersion)]; ok {
-			eol = fmt.Sprintf("%v", cycle.EOL)
+		if c, ok := cycleMap[fmt.Sprintf("%s-%s", *instance.Engine, *instance.EngineVersion)]; ok {
+			cycle = &c
 		}
 
-		daysDiff := remainingDays(eol)
+		eol := cycleEOLStatus(types.KindRDSCluster, cycle)
+		var remedy *types.Remediation
+		if cycle != nil {
+			remedy = engineRemediation(*instance.Engine, cycle, *instance.EngineVersion)
+		}
 
-		logrus.Debugf("rds cluster: %s -> %s (%s), [%d]", *instance.DBClusterArn, *instance.Engine, *instance.EngineVersion, int(daysDiff))
+		logrus.Debugf("rds cluster: %s -> %s (%s), [%d]", *instance.DBClusterArn, *instance.Engine, *instance.EngineVersion, eol.RemainingDays)
 		rdsClusters = append(rdsClusters, types.RDSCluster{
 			Engine: *instance.Engine,
 			VersionedResource: types.VersionedResource{
@@ -68,11 +89,11 @@ func extractRds(ctx context.Context, awsClient interfaces.AWSClient) (*types.Inv
 				Arn:            *instance.DBClusterArn,
 				Version:        *instance.EngineVersion,
 				CurrentVersion: currentCycleMap[*instance.Engine],
-				EOL: types.EOLStatus{
-					EOLDate:       eol,
-					RemainingDays: daysDiff,
-					Status:        eolStatus(daysDiff),
-				},
+				EOL:            eol,
+				Labels: tagsToLabels(instance.TagList, func(tag rdstypes.Tag) (*string, *string) {
+					return tag.Key, tag.Value
+				}),
+				Remediation: remedy,
 			},
 		})
 	}
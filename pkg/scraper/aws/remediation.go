@@ -0,0 +1,169 @@
+package aws
+
+import (
+	"cmp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+	"github.com/hashicorp/go-version"
+)
+
+// upgradeRule tells how a product moves between release cycles
+type upgradeRule struct {
+	// stepwise products go through every cycle in between, e.g. EKS one minor version at a time
+	stepwise bool
+	// format turns a cycle into a version of the resource, e.g. 3.12 into the python3.12 runtime
+	format func(cycle string) string
+}
+
+var eksUpgrades = upgradeRule{stepwise: true}
+
+// lambdaRuntimes are the runtimes Lambda supports for new functions, the only ones functions can be
+// switched to (https://docs.aws.amazon.com/lambda/latest/dg/lambda-runtimes.html)
+var lambdaRuntimes = []string{
+	"nodejs20.x", "nodejs22.x", "nodejs24.x",
+	"python3.10", "python3.11", "python3.12", "python3.13", "python3.14",
+	"ruby3.2", "ruby3.3", "ruby3.4",
+}
+
+// lambdaUpgrades switches the runtime of a function directly, keeping the .x of runtimes like nodejs20.x
+func lambdaUpgrades(product, runtime string) upgradeRule {
+	suffix := ""
+	if len(runtime) > 2 && runtime[len(runtime)-2:] == ".x" {
+		suffix = ".x"
+	}
+	return upgradeRule{format: func(cycle string) string { return product + cycle + suffix }}
+}
+
+// rdsRemediation recommends the newest engine version an RDS cluster can be upgraded to, through the
+// shortest sequence of valid upgrade targets, e.g. 5.7.mysql_aurora.2.11.2 -> 5.7.mysql_aurora.2.12.1 ->
+// 8.0.mysql_aurora.3.05.2. Clusters which cannot leave the cycle they are on get none.
+func rdsRemediation(engineVersions []rdstypes.DBEngineVersion, current *types.ProductCycle, from string) *types.Remediation {
+	if current == nil {
+		return nil
+	}
+	next := map[string][]string{}
+	for _, engineVersion := range engineVersions {
+		for _, target := range engineVersion.ValidUpgradeTarget {
+			next[aws.ToString(engineVersion.EngineVersion)] = append(next[aws.ToString(engineVersion.EngineVersion)], aws.ToString(target.EngineVersion))
+		}
+	}
+
+	// Breadth-first, so that the first path found to a version is one of the shortest
+	previous := map[string]string{from: ""}
+	newest := from
+	queue := []string{from}
+	for len(queue) > 0 {
+		v := queue[0]
+		queue = queue[1:]
+		for _, target := range next[v] {
+			if _, ok := previous[target]; ok {
+				continue
+			}
+			previous[target] = v
+			queue = append(queue, target)
+			if compareEngineVersions(target, newest) > 0 {
+				newest = target
+			}
+		}
+	}
+	if inCycle(newest, current.Cycle) {
+		return nil
+	}
+
+	path := []string{}
+	for v := newest; v != from; v = previous[v] {
+		path = append([]string{v}, path...)
+	}
+	return &types.Remediation{TargetVersion: newest, Path: path}
+}
+
+// compareEngineVersions compares engine versions by their numbers, e.g. 8.0.mysql_aurora.3.05.2 as 8.0.3.5.2
+func compareEngineVersions(v1, v2 string) int {
+	n1, n2 := engineVersionNumbers(v1), engineVersionNumbers(v2)
+	for i := 0; i < len(n1) && i < len(n2); i++ {
+		if n1[i] != n2[i] {
+			return cmp.Compare(n1[i], n2[i])
+		}
+	}
+	return cmp.Compare(len(n1), len(n2))
+}
+
+func engineVersionNumbers(v string) []int {
+	numbers := []int{}
+	for _, field := range strings.FieldsFunc(v, func(r rune) bool { return r < '0' || r > '9' }) {
+		n, err := strconv.Atoi(field)
+		if err == nil {
+			numbers = append(numbers, n)
+		}
+	}
+	return numbers
+}
+
+// inCycle tells whether a version belongs to a cycle, e.g. 8.0.mysql_aurora.3.05.2 to 8.0
+func inCycle(v, cycle string) bool {
+	return v == cycle || strings.HasPrefix(v, cycle+".")
+}
+
+// remediation recommends the newest cycle of a product as the upgrade target of a resource on the
+// given cycle, along with the versions to upgrade through. Targets are the versions the resource can
+// be upgraded to, cycles without any are skipped; nil allows every cycle. Resources on the newest
+// cycle, or on a cycle the product does not list, get none.
+func remediation(cycles []types.ProductCycle, current *types.ProductCycle, rule upgradeRule, targets []string) *types.Remediation {
+	if current == nil {
+		return nil
+	}
+	from, err := version.NewVersion(current.Cycle)
+	if err != nil {
+		return nil
+	}
+
+	format := rule.format
+	if format == nil {
+		format = func(cycle string) string { return cycle }
+	}
+	allowed := func(cycle string) bool {
+		if targets == nil {
+			return true
+		}
+		for _, target := range targets {
+			if inCycle(target, format(cycle)) {
+				return true
+			}
+		}
+		return false
+	}
+
+	newer := []*version.Version{}
+	names := map[*version.Version]string{}
+	for _, cycle := range cycles {
+		v, err := version.NewVersion(cycle.Cycle)
+		if err != nil || !v.GreaterThan(from) || !allowed(cycle.Cycle) {
+			continue
+		}
+		newer = append(newer, v)
+		names[v] = cycle.Cycle
+	}
+	if len(newer) == 0 {
+		return nil
+	}
+	sort.Sort(version.Collection(newer))
+
+	path := []string{}
+	if rule.stepwise {
+		for _, v := range newer {
+			path = append(path, format(names[v]))
+		}
+	} else {
+		path = append(path, format(names[newer[len(newer)-1]]))
+	}
+
+	return &types.Remediation{
+		TargetVersion: path[len(path)-1],
+		Path:          path,
+	}
+}
@@ -0,0 +1,79 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemediation(t *testing.T) {
+	r := require.New(t)
+
+	eks := []types.ProductCycle{{Cycle: "1.29"}, {Cycle: "1.28"}, {Cycle: "1.27"}, {Cycle: "1.26"}}
+	remedy := remediation(eks, &eks[3], eksUpgrades, nil)
+	r.Equal(&types.Remediation{TargetVersion: "1.29", Path: []string{"1.27", "1.28", "1.29"}}, remedy)
+	r.Nil(remediation(eks, &eks[0], eksUpgrades, nil))
+	r.Nil(remediation(eks, nil, eksUpgrades, nil))
+
+	// Lambda runtimes are only switched to the runtimes Lambda supports
+	nodejs := []types.ProductCycle{{Cycle: "26"}, {Cycle: "24"}, {Cycle: "22"}, {Cycle: "16"}}
+	remedy = remediation(nodejs, &nodejs[3], lambdaUpgrades("nodejs", "nodejs16.x"), lambdaRuntimes)
+	r.Equal(&types.Remediation{TargetVersion: "nodejs24.x", Path: []string{"nodejs24.x"}}, remedy)
+	python := []types.ProductCycle{{Cycle: "3.15"}, {Cycle: "3.12"}, {Cycle: "3.8"}}
+	remedy = remediation(python, &python[2], lambdaUpgrades("python", "python3.8"), []string{"python3.11", "python3.12"})
+	r.Equal("python3.12", remedy.TargetVersion)
+	python = []types.ProductCycle{{Cycle: "3.15"}, {Cycle: "3.12"}, {Cycle: "3.1"}}
+	remedy = remediation(python, &python[2], lambdaUpgrades("python", "python3.1"), []string{"python3.12"})
+	r.Equal("python3.12", remedy.TargetVersion)
+}
+
+func TestRDSRemediation(t *testing.T) {
+	r := require.New(t)
+	engineVersion := func(v string, targets ...string) rdstypes.DBEngineVersion {
+		upgrades := []rdstypes.UpgradeTarget{}
+		for _, target := range targets {
+			upgrades = append(upgrades, rdstypes.UpgradeTarget{EngineVersion: aws.String(target)})
+		}
+		return rdstypes.DBEngineVersion{EngineVersion: aws.String(v), ValidUpgradeTarget: upgrades}
+	}
+
+	// Aurora MySQL goes through the latest 2.x release, then to the newest 3.x release in two hops
+	mysql := []rdstypes.DBEngineVersion{
+		engineVersion("5.7.mysql_aurora.2.11.2", "5.7.mysql_aurora.2.12.1"),
+		engineVersion("5.7.mysql_aurora.2.12.1", "8.0.mysql_aurora.3.05.2"),
+		engineVersion("8.0.mysql_aurora.3.05.2", "8.0.mysql_aurora.3.07.1", "5.7.mysql_aurora.2.12.1"),
+		engineVersion("8.0.mysql_aurora.3.07.1"),
+	}
+	cycle := &types.ProductCycle{Cycle: "5.7"}
+	r.Equal(&types.Remediation{
+		TargetVersion: "8.0.mysql_aurora.3.07.1",
+		Path:          []string{"5.7.mysql_aurora.2.12.1", "8.0.mysql_aurora.3.05.2", "8.0.mysql_aurora.3.07.1"},
+	}, rdsRemediation(mysql, cycle, "5.7.mysql_aurora.2.11.2"))
+	r.Nil(rdsRemediation(mysql, &types.ProductCycle{Cycle: "8.0"}, "8.0.mysql_aurora.3.05.2"))
+	r.Nil(rdsRemediation(nil, cycle, "5.7.mysql_aurora.2.12.1"))
+	r.Nil(rdsRemediation(mysql, nil, "5.7.mysql_aurora.2.11.2"))
+
+	// Aurora PostgreSQL 11 only reaches 16 through 15, and takes the shortest path there
+	postgres := []rdstypes.DBEngineVersion{
+		engineVersion("11.9", "11.21", "11.22"),
+		engineVersion("11.21", "12.17", "15.6"),
+		engineVersion("11.22", "12.17"),
+		engineVersion("12.17", "13.13"),
+		engineVersion("13.13", "15.6"),
+		engineVersion("15.6", "16.2"),
+	}
+	r.Equal(&types.Remediation{TargetVersion: "16.2", Path: []string{"11.21", "15.6", "16.2"}},
+		rdsRemediation(postgres, &types.ProductCycle{Cycle: "11"}, "11.9"))
+	r.Equal([]string{"15.6", "16.2"}, rdsRemediation(postgres, &types.ProductCycle{Cycle: "11"}, "11.21").Path)
+}
+
+func TestCompareEngineVersions(t *testing.T) {
+	r := require.New(t)
+	r.Equal(-1, compareEngineVersions("5.7.mysql_aurora.2.12.1", "8.0.mysql_aurora.3.05.2"))
+	r.Equal(1, compareEngineVersions("8.0.mysql_aurora.3.07.1", "8.0.mysql_aurora.3.05.2"))
+	r.Equal(1, compareEngineVersions("16.2", "15.10"))
+	r.Equal(0, compareEngineVersions("11.21", "11.21"))
+}
@@ -3,8 +3,8 @@ package aws
 import (
 	"context"
 	"fmt"
-
 	"sync"
+	"time"
 
 	"github.com/chanzuckerberg/camelot/pkg/scraper/interfaces"
 	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
@@ -12,6 +12,13 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+const source = "aws"
+
+type extractor struct {
+	name    string
+	extract func(ctx context.Context, awsClient interfaces.AWSClient) (*types.InventoryReport, error)
+}
+
 // If profile is not passed it is assumed implicitly based on environment variables, like AWS_PROFILE
 func Scrape(ctx context.Context, opts ...AWSClientOpt) (*types.InventoryReport, error) {
 	awsClient, err := NewAWSClient(ctx, opts...)
@@ -24,39 +31,67 @@ func Scrape(ctx context.Context, opts ...AWSClientOpt) (*types.InventoryReport,
 		regions = []string{"us-east-1", "us-west-2", "us-east-2", "us-west-1"}
 	}
 
-	extractors := []func(ctx context.Context, awsClient interfaces.AWSClient) (*types.InventoryReport, error){
-		extractEksClusterInfo,
-		extractRds,
-		extractLambdas,
-		extractAMIs,
-		extractVolumes,
-		extractACMCertificates,
+	extractors := []extractor{
+		{name: "eks", extract: extractEksClusterInfo},
+		{name: "rds", extract: extractRds},
+		{name: "lambda", extract: extractLambdas},
+		{name: "ami", extract: extractAMIs},
+		{name: "volume", extract: extractVolumes},
+		{name: "acm", extract: extractACMCertificates},
 	}
 
 	var wg sync.WaitGroup
-	wg.Add(len(regions) * len(extractors))
 
 	reports := make([]*types.InventoryReport, len(regions)*len(extractors))
 	index := 0
+	scrapeErrors := []types.ScrapeError{}
 
 	for _, region := range regions {
 		logrus.Debugf("Scraping profile %s, region %s", awsClient.GetProfile(), region)
 		client, err := NewAWSClient(ctx, append(opts, WithRegion(region))...)
 		if err != nil {
-			return nil, fmt.Errorf("failed to load config for profile %s, region %s", awsClient.GetProfile(), region)
+			logrus.Errorf("failed to load config for profile %s, region %s: %s", awsClient.GetProfile(), region, err.Error())
+			scrapeErrors = append(scrapeErrors, types.ScrapeError{
+				Source:  source,
+				Account: awsClient.GetAccountId(),
+				Region:  region,
+				Message: fmt.Sprintf("failed to load config for profile %s: %s", awsClient.GetProfile(), err.Error()),
+			})
+			index += len(extractors)
+			continue
 		}
 
-		for _, extractor := range extractors {
-			go func(client interfaces.AWSClient, extractor func(ctx context.Context, awsClient interfaces.AWSClient) (*types.InventoryReport, error), i int) {
+		for _, e := range extractors {
+			wg.Add(1)
+			go func(client interfaces.AWSClient, e extractor, region string, i int) {
 				defer wg.Done()
 
-				report, err := extractor(ctx, client)
+				start := time.Now()
+				report, err := e.extract(ctx, client)
 				if err != nil {
 					logrus.Errorf("failed to extract inventory: %s", err.Error())
-				} else {
-					reports[i] = report
+					report = &types.InventoryReport{
+						Errors: []types.ScrapeError{{Message: err.Error()}},
+					}
+				}
+				for j := range report.Errors {
+					scrapeError := &report.Errors[j]
+					scrapeError.Source = source
+					scrapeError.Extractor = e.name
+					scrapeError.Account = client.GetAccountId()
+					scrapeError.Region = region
 				}
-			}(client, extractor, index)
+				report.Stats = append(report.Stats, types.ScrapeStat{
+					Source:    source,
+					Extractor: e.name,
+					Account:   client.GetAccountId(),
+					Region:    region,
+					Duration:  time.Since(start).Seconds(),
+					Resources: len(report.Resources),
+					Errors:    len(report.Errors),
+				})
+				reports[i] = report
+			}(client, e, region, index)
 			index++
 		}
 	}
@@ -66,6 +101,7 @@ func Scrape(ctx context.Context, opts ...AWSClientOpt) (*types.InventoryReport,
 	summary.Identity = types.Indentity{
 		AwsAccountNumber: awsClient.GetAccountId(),
 	}
+	summary.Errors = append(scrapeErrors, summary.Errors...)
 
 	return &summary, nil
 }
@@ -5,8 +5,11 @@ import (
 	"strings"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+	"github.com/chanzuckerberg/camelot/pkg/util"
 	"github.com/sirupsen/logrus"
 	"gopkg.in/ini.v1"
 )
@@ -27,14 +30,63 @@ func remainingDays(eol string) int {
 	return int(diff.Hours() / 24)
 }
 
-func eolStatus(days int) types.Status {
-	if days <= 30 {
-		return types.StatusCritical
+func eolStatus(kind types.ResourceKind, days int) types.Status {
+	return util.GetPolicy().Status(kind, days)
+}
+
+// Builds the two-stage status of a release cycle: WARNING once active (bug-fix) support has ended,
+// then the usual countdown to CRITICAL as the end of security support approaches.
+func cycleEOLStatus(kind types.ResourceKind, cycle *types.ProductCycle) types.EOLStatus {
+	if cycle == nil {
+		days := remainingDays("")
+		return types.EOLStatus{RemainingDays: days, Status: eolStatus(kind, days)}
+	}
+
+	eol := ""
+	switch value := cycle.EOL.(type) {
+	case string:
+		eol = value
+	case bool:
+		if value {
+			eol = "true"
+		}
+	}
+	days := remainingDays(eol)
+
+	status := eolStatus(kind, days)
+	if status == types.StatusValid && supportEnded(cycle.Support) {
+		status = types.StatusWarning
+	}
+
+	support, _ := cycle.Support.(string)
+	extendedSupport, _ := cycle.ExtendedSupport.(string)
+	lts := false
+	switch value := cycle.LTS.(type) {
+	case string: // the date the cycle became LTS
+		lts = len(value) > 0
+	case bool:
+		lts = value
+	}
+
+	return types.EOLStatus{
+		EOLDate:             eol,
+		SupportDate:         support,
+		ExtendedSupportDate: extendedSupport,
+		LTS:                 lts,
+		RemainingDays:       days,
+		Status:              status,
 	}
-	if days <= 90 {
-		return types.StatusWarning
+}
+
+// Support is either the date active support ends, or a flag telling whether it is still active
+func supportEnded(support interface{}) bool {
+	switch value := support.(type) {
+	case string:
+		return len(value) > 0 && remainingDays(value) <= 0
+	case bool:
+		return !value
 	}
-	return types.StatusValid
+	return false
 }
 
 func GetAWSProfiles() ([]string, error) {
@@ -54,3 +106,23 @@ func GetAWSProfiles() ([]string, error) {
 	}
 	return profiles, nil
 }
+
+// tagsToLabels converts the tag list of any AWS service to labels; resources without tags get no labels
+func tagsToLabels[T any](tags []T, keyValue func(tag T) (*string, *string)) map[string]string {
+	if len(tags) == 0 {
+		return nil
+	}
+	labels := map[string]string{}
+	for _, tag := range tags {
+		key, value := keyValue(tag)
+		if key == nil {
+			continue
+		}
+		labels[*key] = aws.ToString(value)
+	}
+	return labels
+}
+
+func ec2TagsToLabels(tags []ec2types.Tag) map[string]string {
+	return tagsToLabels(tags, func(tag ec2types.Tag) (*string, *string) { return tag.Key, tag.Value })
+}
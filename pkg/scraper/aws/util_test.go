@@ -15,9 +15,49 @@ func TestRemainingDays(t *testing.T) {
 	r.GreaterOrEqual(days, 9)
 }
 
+func TestCycleEOLStatus(t *testing.T) {
+	r := require.New(t)
+	date := func(days int) string {
+		return time.Now().AddDate(0, 0, days).Format("2006-01-02")
+	}
+
+	eol := cycleEOLStatus(types.KindEKSCluster, nil)
+	r.Equal(types.StatusValid, string(eol.Status))
+
+	// Actively supported
+	eol = cycleEOLStatus(types.KindEKSCluster, &types.ProductCycle{Support: date(200), EOL: date(400), LTS: true})
+	r.Equal(types.StatusValid, string(eol.Status))
+	r.Equal(date(200), eol.SupportDate)
+	r.Equal(date(400), eol.EOLDate)
+	r.True(eol.LTS)
+
+	// Security fixes only
+	eol = cycleEOLStatus(types.KindEKSCluster, &types.ProductCycle{Support: date(-10), EOL: date(400), ExtendedSupport: date(800)})
+	r.Equal(types.StatusWarning, string(eol.Status))
+	r.Equal(date(800), eol.ExtendedSupportDate)
+	r.False(eol.LTS)
+
+	eol = cycleEOLStatus(types.KindEKSCluster, &types.ProductCycle{Support: false, EOL: date(400)})
+	r.Equal(types.StatusWarning, string(eol.Status))
+	r.Empty(eol.SupportDate)
+
+	// Approaching the end of security support
+	eol = cycleEOLStatus(types.KindEKSCluster, &types.ProductCycle{Support: date(-100), EOL: date(10)})
+	r.Equal(types.StatusCritical, string(eol.Status))
+
+	eol = cycleEOLStatus(types.KindEKSCluster, &types.ProductCycle{Support: true, EOL: true})
+	r.Equal(types.StatusCritical, string(eol.Status))
+	r.Equal("true", eol.EOLDate)
+
+	eol = cycleEOLStatus(types.KindEKSCluster, &types.ProductCycle{Support: true, EOL: false, LTS: "2023-10-24"})
+	r.Equal(types.StatusValid, string(eol.Status))
+	r.Empty(eol.EOLDate)
+	r.True(eol.LTS)
+}
+
 func TestEolStatus(t *testing.T) {
 	r := require.New(t)
-	r.Equal(types.StatusCritical, string(eolStatus(0)))
-	r.Equal(types.StatusCritical, string(eolStatus(15)))
-	r.Equal(types.StatusWarning, string(eolStatus(80)))
+	r.Equal(types.StatusCritical, string(eolStatus(types.KindEKSCluster, 0)))
+	r.Equal(types.StatusCritical, string(eolStatus(types.KindEKSCluster, 15)))
+	r.Equal(types.StatusWarning, string(eolStatus(types.KindEKSCluster, 80)))
 }
@@ -28,6 +28,7 @@ func extractVolumes(ctx context.Context, awsClient interfaces.AWSClient) (*types
 					RemainingDays: 9999,
 					Status:        types.StatusValid,
 				},
+				Labels: ec2TagsToLabels(volume.Tags),
 			},
 		})
 	}
@@ -0,0 +1,108 @@
+package github
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+type codeownersRule struct {
+	pattern *regexp.Regexp
+	owners  []string
+}
+
+// codeowners are the rules of a CODEOWNERS file; the last matching rule wins
+type codeowners []codeownersRule
+
+var codeownersLocations = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// loadCodeowners reads the CODEOWNERS file of a cloned repo, a repo without one has no rules
+func loadCodeowners(repoDir string) (codeowners, error) {
+	for _, location := range codeownersLocations {
+		f, err := os.Open(filepath.Join(repoDir, location))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		rules := codeowners{}
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if len(line) == 0 || strings.HasPrefix(line, "#") {
+				continue
+			}
+			fields := strings.Fields(line)
+			owners := []string{}
+			for _, owner := range fields[1:] {
+				if strings.HasPrefix(owner, "#") {
+					break
+				}
+				owners = append(owners, owner)
+			}
+			rules = append(rules, codeownersRule{pattern: codeownersPattern(fields[0]), owners: owners})
+		}
+		return rules, scanner.Err()
+	}
+	return codeowners{}, nil
+}
+
+// codeownersPattern translates a gitignore style pattern to a regexp. Patterns without a slash in
+// the middle match at any depth, and a pattern matching a directory matches everything below it.
+func codeownersPattern(pattern string) *regexp.Regexp {
+	anchored := strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+	pattern = strings.Trim(pattern, "/")
+
+	var sb strings.Builder
+	sb.WriteString("^")
+	if !anchored {
+		sb.WriteString("(.*/)?")
+	}
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			sb.WriteString("(.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			sb.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			sb.WriteString("[^/]*")
+		case pattern[i] == '?':
+			sb.WriteString("[^/]")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(pattern[i])))
+		}
+	}
+	sb.WriteString("(/.*)?$")
+	return regexp.MustCompile(sb.String())
+}
+
+// owner returns the first owner of the path (relative to the repo root, "" for the whole repo),
+// without the @ and the org of teams: @org/payments is payments
+func (c codeowners) owner(path string) string {
+	path = strings.Trim(filepath.ToSlash(path), "/")
+	if path == "." {
+		path = ""
+	}
+	for i := len(c) - 1; i >= 0; i-- {
+		if !c[i].pattern.MatchString(path) {
+			continue
+		}
+		if len(c[i].owners) == 0 {
+			// A rule without owners unassigns the path
+			return ""
+		}
+		owner := strings.TrimPrefix(c[i].owners[0], "@")
+		if _, team, ok := strings.Cut(owner, "/"); ok {
+			return team
+		}
+		return owner
+	}
+	return ""
+}
@@ -5,8 +5,10 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
+	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/pkg/errors"
@@ -17,6 +19,26 @@ type ModuleRef struct {
 	Timestamp time.Time
 }
 
+// moduleSource is the source of a module block, and where it is declared
+type moduleSource struct {
+	Source    string
+	File      string // relative to the directory searched
+	StartLine int
+	EndLine   int
+}
+
+// reference locates the declaration of a module in a repo of the org, on its main branch
+func (m moduleSource) reference(githubOrg, repo string) types.GitOpsReference {
+	return types.GitOpsReference{
+		Repo:      githubOrg + "/" + repo,
+		Branch:    "main",
+		Path:      filepath.Dir(m.File),
+		File:      m.File,
+		StartLine: m.StartLine,
+		EndLine:   m.EndLine,
+	}
+}
+
 var moduleBlockSchema = &hcl.BodySchema{
 	Blocks: []hcl.BlockHeaderSchema{
 		{
@@ -26,8 +48,8 @@ var moduleBlockSchema = &hcl.BodySchema{
 	},
 }
 
-func findModules(dir string) ([]string, error) {
-	modules := []string{}
+func findModules(dir string) ([]moduleSource, error) {
+	modules := []moduleSource{}
 	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
@@ -75,7 +97,12 @@ func findModules(dir string) ([]string, error) {
 			if diags.HasErrors() {
 				return fmt.Errorf("terraform code has errors: %w", diags.Errs()[0])
 			}
-			modules = append(modules, source.AsString())
+			modules = append(modules, moduleSource{
+				Source:    source.AsString(),
+				File:      strings.TrimPrefix(path, dir+"/"),
+				StartLine: sourceAttr.Range.Start.Line,
+				EndLine:   sourceAttr.Range.End.Line,
+			})
 		}
 		return nil
 	})
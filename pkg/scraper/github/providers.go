@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+	"github.com/chanzuckerberg/camelot/pkg/util"
 	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
@@ -50,7 +51,8 @@ type HashicorpProviderResponse struct {
 	Versions    []string  `json:"versions"`
 }
 
-func findProviders(repo, branch, dir string) ([]types.Versioned, error) {
+// findProviders lists the providers required in the Terraform files of a repo of the org, cloned in dir
+func findProviders(githubOrg, repo, branch, dir string) ([]types.Versioned, error) {
 	providers := []types.Versioned{}
 	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
@@ -127,6 +129,7 @@ func findProviders(repo, branch, dir string) ([]types.Versioned, error) {
 					}
 
 					relativePath := strings.TrimPrefix(filepath.Dir(path), dir+"/")
+					declaration := attr.Range
 					providers = append(providers, types.TfcProvider{
 						VersionedResource: types.VersionedResource{
 							ID:             providerID,
@@ -135,9 +138,12 @@ func findProviders(repo, branch, dir string) ([]types.Versioned, error) {
 							CurrentVersion: mostCurrentVer,
 							Parents:        []types.ParentResource{{Kind: types.KindGithubRepo, ID: repo}, {Kind: types.KindGitPath, ID: relativePath}},
 							GitOpsReference: types.GitOpsReference{
-								Repo:   repo,
-								Branch: branch,
-								Path:   relativePath,
+								Repo:      githubOrg + "/" + repo,
+								Branch:    branch,
+								Path:      relativePath,
+								File:      strings.TrimPrefix(path, dir+"/"),
+								StartLine: declaration.Start.Line,
+								EndLine:   declaration.End.Line,
 							},
 							EOL: eol,
 						},
@@ -155,7 +161,7 @@ func getProviderDetails(providerID string) (*HashicorpProviderResponse, error) {
 		return &val, nil
 	}
 
-	p, err := url.Parse(fmt.Sprintf("https://registry.terraform.io/v1/providers/%s", providerID))
+	p, err := url.Parse(fmt.Sprintf("%s/providers/%s", util.GetEndpoints().TerraformRegistry, providerID))
 	if err != nil {
 		return nil, fmt.Errorf("unable to parse endpoint url")
 	}
@@ -165,7 +171,7 @@ func getProviderDetails(providerID string) (*HashicorpProviderResponse, error) {
 		return nil, fmt.Errorf("http Get call to terraform registry failed")
 	}
 
-	res, err := http.DefaultClient.Do(req)
+	res, err := util.HTTPClient().Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -17,7 +18,15 @@ import (
 
 var artifacthubCache = cmap.New[HashicorpProviderResponse]()
 
+// ResetCaches forgets the provider versions and tag dates fetched so far, so that a long-running process
+// sees the latest ones on its next scrape
+func ResetCaches() {
+	artifacthubCache.Clear()
+	tagCache.Clear()
+}
+
 func Scrape(ctx context.Context, githubOrg string) (*types.InventoryReport, error) {
+	start := time.Now()
 	githubToken := os.Getenv("GITHUB_TOKEN")
 	allRepos, err := getOrgRepos(ctx, githubToken, githubOrg)
 	if err != nil {
@@ -32,8 +41,18 @@ func Scrape(ctx context.Context, githubOrg string) (*types.InventoryReport, erro
 		Resources: []types.Versioned{},
 	}
 
+	repoError := func(repo string, err error) types.ScrapeError {
+		return types.ScrapeError{
+			Source:   "github",
+			Resource: &types.ParentResource{Kind: types.KindGithubRepo, ID: repo},
+			Message:  err.Error(),
+		}
+	}
+
 	moduleUsageMap := map[string]map[string]int{}
-	repoModuleReferenceMap := map[string]map[string]bool{}
+	// versioned module reference -> repo -> where the repo declares it
+	repoModuleReferenceMap := map[string]map[string][]types.GitOpsReference{}
+	repoOwners := map[string]string{}
 
 	for _, repo := range allRepos {
 		tempDir, err := os.MkdirTemp("/tmp", *repo.Name)
@@ -44,27 +63,39 @@ func Scrape(ctx context.Context, githubOrg string) (*types.InventoryReport, erro
 
 		err = cloneRepo(*repo.CloneURL, *repo.Name, tempDir)
 		if err != nil {
-			return nil, fmt.Errorf("unable to clone repo: %w", err)
+			logrus.Errorf("Unable to clone repo %s: %s", *repo.Name, err.Error())
+			report.Errors = append(report.Errors, repoError(*repo.Name, fmt.Errorf("unable to clone repo: %w", err)))
+			continue
+		}
+		owners, err := loadCodeowners(filepath.Join(tempDir, *repo.Name))
+		if err != nil {
+			logrus.Debugf("Unable to read CODEOWNERS in %s: %s", *repo.Name, err.Error())
 		}
-		providers, err := findProviders(*repo.Name, "main", filepath.Join(tempDir, *repo.Name))
+		repoOwners[*repo.Name] = owners.owner("")
+
+		providers, err := findProviders(githubOrg, *repo.Name, "main", filepath.Join(tempDir, *repo.Name))
 		if err == nil {
-			report.Resources = append(report.Resources, providers...)
+			for _, provider := range providers {
+				resource := provider.GetVersionedResource()
+				resource.Owner = owners.owner(resource.GitOpsReference.Path)
+				report.Resources = append(report.Resources, types.SetVersionedResource(provider, resource))
+			}
 		} else {
 			logrus.Debugf("Unable to read providers in %s: %s", *repo.Name, err.Error())
 		}
-		modules, err := findModules(tempDir)
+		modules, err := findModules(filepath.Join(tempDir, *repo.Name))
 		if err != nil {
 			logrus.Debugf("Unable to read modules in %s (%s): %s", *repo.Name, tempDir, err.Error())
 			continue
 		}
 		for _, module := range modules {
 			// Only track versioned module references
-			if !strings.Contains(module, "?ref=") {
+			if !strings.Contains(module.Source, "?ref=") {
 				continue
 			}
-			gitUrl, modulePath, ref, err := parseModuleSource(module)
+			gitUrl, modulePath, ref, err := parseModuleSource(module.Source)
 			if err != nil {
-				logrus.Errorf("Unable to parse module source %s in repo %s: %s", module, *repo.Name, err.Error())
+				logrus.Errorf("Unable to parse module source %s in repo %s: %s", module.Source, *repo.Name, err.Error())
 				continue
 			}
 
@@ -75,30 +106,28 @@ func Scrape(ctx context.Context, githubOrg string) (*types.InventoryReport, erro
 
 			versionedModuleReference := fmt.Sprintf("%s?ref=%s", moduleReference, ref)
 			if _, ok := repoModuleReferenceMap[versionedModuleReference]; !ok {
-				repoModuleReferenceMap[versionedModuleReference] = map[string]bool{}
+				repoModuleReferenceMap[versionedModuleReference] = map[string][]types.GitOpsReference{}
 			}
 			moduleUsageMap[moduleReference][ref] = moduleUsageMap[moduleReference][ref] + 1
-			repoModuleReferenceMap[versionedModuleReference][*repo.Name] = true
+			repoModuleReferenceMap[versionedModuleReference][*repo.Name] = append(repoModuleReferenceMap[versionedModuleReference][*repo.Name], module.reference(githubOrg, *repo.Name))
 			logrus.Debugf("module: repo=%s, name=%s, ref=%s", gitUrl, modulePath, ref)
 		}
 
 		defaultBranch, err := getDefaultBranch(githubToken, githubOrg, *repo.Name)
 		if err != nil {
 			logrus.Errorf("Unable to get default branch for %s: %s", *repo.Name, err.Error())
+			report.Errors = append(report.Errors, repoError(*repo.Name, fmt.Errorf("unable to get default branch: %w", err)))
 			continue
 		}
 		date, err := getCommitDate(githubToken, githubOrg, *repo.Name, defaultBranch)
 		if err != nil {
 			logrus.Errorf("Unable to get commit date for %s: %s", *repo.Name, err.Error())
-			date = &time.Time{}
+			report.Errors = append(report.Errors, repoError(*repo.Name, fmt.Errorf("unable to get commit date: %w", err)))
 			continue
 		}
 
 		eolDate := date.AddDate(3, 0, 0)
-		var status types.Status = types.StatusValid
-		if time.Now().After(eolDate) {
-			status = types.StatusWarning
-		}
+		remainingDays := util.RemainingDays(eolDate)
 		report.Resources = append(report.Resources, types.GitRepo{
 			VersionedResource: types.VersionedResource{
 				ID:      *repo.Name,
@@ -106,10 +135,11 @@ func Scrape(ctx context.Context, githubOrg string) (*types.InventoryReport, erro
 				Arn:     "",
 				Parents: []types.ParentResource{{Kind: types.KindGithubOrg, ID: githubOrg}},
 				Version: "0.0.0",
+				Owner:   repoOwners[*repo.Name],
 				EOL: types.EOLStatus{
 					EOLDate:       eolDate.Format("2006-01-02"),
-					RemainingDays: util.RemainingDays(eolDate),
-					Status:        status,
+					RemainingDays: remainingDays,
+					Status:        util.GetPolicy().Status(types.KindGithubRepo, remainingDays),
 				},
 			},
 		})
@@ -137,6 +167,7 @@ func Scrape(ctx context.Context, githubOrg string) (*types.InventoryReport, erro
 			timestamp, err := getTagCommitDate(githubToken, org, repo, ref)
 			if err != nil {
 				logrus.Errorf("Unable to get timestamp for %s: %s", ref, err.Error())
+				report.Errors = append(report.Errors, repoError(org+"/"+repo, fmt.Errorf("unable to get timestamp for %s: %w", ref, err)))
 				timestamp = &time.Time{}
 			}
 			moduleRefs = append(moduleRefs, ModuleRef{
@@ -151,25 +182,50 @@ func Scrape(ctx context.Context, githubOrg string) (*types.InventoryReport, erro
 			logrus.Debugf("\t%s\t%d\n", ref, moduleVersionDistribution[ref.Ref])
 		}
 
-		for index, ref := range moduleRefs {
-			repos := repoModuleReferenceMap[fmt.Sprintf("%s?ref=%s", module, ref.Ref)]
-			var status types.Status
-			status = types.StatusWarning
-			eolDate := ref.Timestamp.Format("2006-01-02")
-			if index == 0 {
-				status = types.StatusValid
-				// Assume modules are supported for 3 years
-				eolDate = ref.Timestamp.AddDate(3, 0, 0).Format("2006-01-02")
-			}
-			for repo := range repos {
-				report.Resources = append(report.Resources, types.TerraformModule{
+		report.Resources = append(report.Resources, moduleRefResources(githubOrg, module, moduleRefs, repoModuleReferenceMap, repoOwners)...)
+	}
+
+	report.Stats = []types.ScrapeStat{{
+		Source:    "github",
+		Duration:  time.Since(start).Seconds(),
+		Resources: len(report.Resources),
+		Errors:    len(report.Errors),
+	}}
+	return report, nil
+}
+
+// moduleRefResources lists a resource per declaration of a module at each of its refs, newest first.
+// Declarations are located by their repo and directory, as a repo may pin the module in several stacks.
+func moduleRefResources(githubOrg, module string, moduleRefs []ModuleRef, declarations map[string]map[string][]types.GitOpsReference, repoOwners map[string]string) []types.Versioned {
+	resources := []types.Versioned{}
+	for index, ref := range moduleRefs {
+		repos := declarations[fmt.Sprintf("%s?ref=%s", module, ref.Ref)]
+		var status types.Status
+		status = types.StatusWarning
+		eolDate := ref.Timestamp.Format("2006-01-02")
+		if index == 0 {
+			status = types.StatusValid
+			// Assume modules are supported for 3 years
+			eolDate = ref.Timestamp.AddDate(3, 0, 0).Format("2006-01-02")
+		}
+
+		repoNames := make([]string, 0, len(repos))
+		for repo := range repos {
+			repoNames = append(repoNames, repo)
+		}
+		sort.Strings(repoNames)
+		for _, repo := range repoNames {
+			for _, location := range repos[repo] {
+				resources = append(resources, types.TerraformModule{
 					VersionedResource: types.VersionedResource{
-						ID:             strings.Replace(module, fmt.Sprintf("github.com/%s/", githubOrg), "", 1),
-						Kind:           types.KindTerrfaormModule,
-						Arn:            "",
-						Parents:        []types.ParentResource{{Kind: types.KindGithubRepo, ID: repo}},
-						Version:        ref.Ref,
-						CurrentVersion: moduleRefs[0].Ref,
+						ID:              strings.Replace(module, fmt.Sprintf("github.com/%s/", githubOrg), "", 1),
+						Kind:            types.KindTerrfaormModule,
+						Arn:             "",
+						Parents:         []types.ParentResource{{Kind: types.KindGithubRepo, ID: repo}, {Kind: types.KindGitPath, ID: location.Path}},
+						Version:         ref.Ref,
+						CurrentVersion:  moduleRefs[0].Ref,
+						Owner:           repoOwners[repo],
+						GitOpsReference: location,
 						EOL: types.EOLStatus{
 							EOLDate:       eolDate,
 							RemainingDays: 0,
@@ -179,8 +235,6 @@ func Scrape(ctx context.Context, githubOrg string) (*types.InventoryReport, erro
 				})
 			}
 		}
-
 	}
-
-	return report, nil
+	return resources
 }
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os/exec"
 	"path/filepath"
 	"regexp"
@@ -13,6 +14,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/chanzuckerberg/camelot/pkg/util"
 	"github.com/google/go-github/v53/github"
 	cmap "github.com/orcaman/concurrent-map/v2"
 	"github.com/pkg/errors"
@@ -39,7 +41,7 @@ func getTagCommitDate(token, owner, repo, ref string) (*time.Time, error) {
 	}
 
 	// Treat the reference as a tag
-	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/git/ref/tags/%s", owner, repo, ref)
+	url := fmt.Sprintf("%s/repos/%s/%s/git/ref/tags/%s", util.GetEndpoints().GithubAPI, owner, repo, ref)
 	m, err := getGithubResponse(token, url)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get tag details: %w", err)
@@ -61,7 +63,7 @@ func getTagCommitDate(token, owner, repo, ref string) (*time.Time, error) {
 
 	// If the tag is a reference to another tag, get the sha of the tag
 	if refType == "tag" {
-		url := fmt.Sprintf("https://api.github.com/repos/%s/%s/git/tags/%s", owner, repo, sha)
+		url := fmt.Sprintf("%s/repos/%s/%s/git/tags/%s", util.GetEndpoints().GithubAPI, owner, repo, sha)
 		m, err := getGithubResponse(token, url)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get tag details: %w", err)
@@ -87,7 +89,7 @@ func getTagCommitDate(token, owner, repo, ref string) (*time.Time, error) {
 }
 
 func getDefaultBranch(token, owner, repo string) (string, error) {
-	url := fmt.Sprintf("https://api.github.com/repos/%s/%s", owner, repo)
+	url := fmt.Sprintf("%s/repos/%s/%s", util.GetEndpoints().GithubAPI, owner, repo)
 	m, err := getGithubResponse(token, url)
 	if err != nil {
 		return "", fmt.Errorf("failed to get repo details: %w", err)
@@ -101,7 +103,7 @@ func getDefaultBranch(token, owner, repo string) (string, error) {
 }
 
 func getCommitDate(token, owner, repo, sha string) (*time.Time, error) {
-	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/commits/%s", owner, repo, sha)
+	url := fmt.Sprintf("%s/repos/%s/%s/commits/%s", util.GetEndpoints().GithubAPI, owner, repo, sha)
 	m, err := getGithubResponse(token, url)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get commit details: %w", err)
@@ -136,7 +138,7 @@ func getGithubResponse(token, url string) (map[string]interface{}, error) {
 	}
 	req.Header.Set("Accept", "application/vnd.github.v3+json")
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := util.HTTPClient().Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -207,14 +209,19 @@ func versionSort(moduleRefs []ModuleRef) []ModuleRef {
 }
 
 func getOrgRepos(ctx context.Context, githubToken, githubOrg string) ([]*github.Repository, error) {
-	client := github.NewClient(nil)
+	client := github.NewClient(util.HTTPClient())
 	if len(githubToken) > 0 {
 		ts := oauth2.StaticTokenSource(
 			&oauth2.Token{AccessToken: githubToken},
 		)
-		tc := oauth2.NewClient(ctx, ts)
+		tc := oauth2.NewClient(context.WithValue(ctx, oauth2.HTTPClient, util.HTTPClient()), ts)
 		client = github.NewClient(tc)
 	}
+	baseURL, err := url.Parse(util.GetEndpoints().GithubAPI + "/")
+	if err != nil {
+		return nil, fmt.Errorf("invalid github api url: %w", err)
+	}
+	client.BaseURL = baseURL
 	opt := &github.RepositoryListByOrgOptions{
 		ListOptions: github.ListOptions{PerPage: 100},
 	}
@@ -2,9 +2,16 @@ package github
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+	"github.com/chanzuckerberg/camelot/pkg/util"
 	"github.com/stretchr/testify/require"
 )
 
@@ -85,6 +92,52 @@ func TestGetProviderDetails(t *testing.T) {
 	r.NotEmpty(p.Version)
 }
 
+func TestGetProviderDetailsMirror(t *testing.T) {
+	r := require.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		r.Equal("/v1/providers/example/mirrored", req.URL.Path)
+		_, _ = w.Write([]byte(`{"owner":"example","name":"mirrored","version":"1.2.3"}`))
+	}))
+	defer server.Close()
+
+	util.SetEndpoints(util.Endpoints{TerraformRegistry: server.URL + "/v1"})
+	defer util.SetEndpoints(util.DefaultEndpoints)
+
+	p, err := getProviderDetails("example/mirrored")
+	r.NoError(err)
+	r.Equal("mirrored", p.Name)
+	r.Equal("1.2.3", p.Version)
+}
+
+func TestResetCaches(t *testing.T) {
+	r := require.New(t)
+
+	latest := "1.2.3"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte(`{"owner":"example","name":"cached","version":"` + latest + `"}`))
+	}))
+	defer server.Close()
+
+	util.SetEndpoints(util.Endpoints{TerraformRegistry: server.URL})
+	defer util.SetEndpoints(util.DefaultEndpoints)
+	defer ResetCaches()
+
+	p, err := getProviderDetails("example/cached")
+	r.NoError(err)
+	r.Equal("1.2.3", p.Version)
+
+	// A scrape sees the versions fetched by the previous ones until the caches are reset
+	latest = "1.3.0"
+	p, err = getProviderDetails("example/cached")
+	r.NoError(err)
+	r.Equal("1.2.3", p.Version)
+	ResetCaches()
+	p, err = getProviderDetails("example/cached")
+	r.NoError(err)
+	r.Equal("1.3.0", p.Version)
+}
+
 func TestVersionConstraint(t *testing.T) {
 	r := require.New(t)
 	res := checkProviderVersion("1.0.0", "1.0.0")
@@ -151,3 +204,140 @@ func TestFindOldestConstraintConstraint(t *testing.T) {
 	r.NoError(err)
 	r.Equal("1.0.0", v.String())
 }
+
+func TestCodeowners(t *testing.T) {
+	r := require.New(t)
+	dir := t.TempDir()
+
+	owners, err := loadCodeowners(dir)
+	r.NoError(err)
+	r.Equal("", owners.owner(""))
+
+	r.NoError(os.MkdirAll(filepath.Join(dir, ".github"), 0755))
+	r.NoError(os.WriteFile(filepath.Join(dir, ".github", "CODEOWNERS"), []byte(`
+# Default owners
+*                 @chanzuckerberg/infra
+terraform/        @chanzuckerberg/platform # any terraform dir
+/envs/prod/**     @alice @chanzuckerberg/payments
+/envs/prod/shared
+docs/*.md         @bob
+`), 0644))
+
+	owners, err = loadCodeowners(dir)
+	r.NoError(err)
+	r.Equal("infra", owners.owner(""))
+	r.Equal("infra", owners.owner("."))
+	r.Equal("infra", owners.owner("modules/vpc"))
+	r.Equal("platform", owners.owner("terraform"))
+	r.Equal("platform", owners.owner("stacks/terraform/eks"))
+	r.Equal("alice", owners.owner("envs/prod/api"))
+	r.Equal("infra", owners.owner("envs/staging/api"))
+	r.Equal("", owners.owner("envs/prod/shared"))
+	r.Equal("bob", owners.owner("docs/setup.md"))
+	r.Equal("infra", owners.owner("docs/guides/setup.md"))
+}
+
+func TestFindModules(t *testing.T) {
+	r := require.New(t)
+	dir := t.TempDir()
+	r.NoError(os.MkdirAll(filepath.Join(dir, "envs", "prod"), 0755))
+	r.NoError(os.WriteFile(filepath.Join(dir, "envs", "prod", "main.tf"), []byte(`locals {
+  name = "prod"
+}
+
+module "vpc" {
+  source = "git@github.com:chanzuckerberg/cztack//aws-vpc?ref=v0.40.0"
+  name   = local.name
+}
+`), 0644))
+
+	modules, err := findModules(dir)
+	r.NoError(err)
+	r.Equal([]moduleSource{{
+		Source:    "git@github.com:chanzuckerberg/cztack//aws-vpc?ref=v0.40.0",
+		File:      "envs/prod/main.tf",
+		StartLine: 6,
+		EndLine:   6,
+	}}, modules)
+
+	// Declarations link to the repo of the org on GitHub
+	reference := modules[0].reference("chanzuckerberg", "infra")
+	r.Equal(types.GitOpsReference{Repo: "chanzuckerberg/infra", Branch: "main", Path: "envs/prod", File: "envs/prod/main.tf", StartLine: 6, EndLine: 6}, reference)
+	r.Equal("https://github.com/chanzuckerberg/infra", util.RepoURL(reference))
+	r.Equal("https://github.com/chanzuckerberg/infra/tree/main/envs/prod", util.PathURL(reference))
+}
+
+func TestFindProviders(t *testing.T) {
+	r := require.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_, _ = w.Write([]byte(`{"owner":"hashicorp","name":"aws","version":"5.40.0","published_at":"2024-03-07T00:00:00Z"}`))
+	}))
+	defer server.Close()
+	util.SetEndpoints(util.Endpoints{TerraformRegistry: server.URL + "/v1"})
+	defer util.SetEndpoints(util.DefaultEndpoints)
+
+	dir := t.TempDir()
+	r.NoError(os.MkdirAll(filepath.Join(dir, "stacks"), 0755))
+	r.NoError(os.WriteFile(filepath.Join(dir, "stacks", "versions.tf"), []byte(`terraform {
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "~> 2.0"
+    }
+  }
+}
+`), 0644))
+
+	providers, err := findProviders("chanzuckerberg", "infra", "main", dir)
+	r.NoError(err)
+	r.Len(providers, 1)
+	provider := providers[0].GetVersionedResource()
+	r.Equal("hashicorp/aws", provider.ID)
+	r.Equal(types.Status(types.StatusCritical), provider.EOL.Status)
+	r.Equal(types.GitOpsReference{Repo: "chanzuckerberg/infra", Branch: "main", Path: "stacks", File: "stacks/versions.tf", StartLine: 3, EndLine: 6},
+		provider.GitOpsReference)
+	r.Equal([]types.ParentResource{{Kind: types.KindGithubRepo, ID: "infra"}, {Kind: types.KindGitPath, ID: "stacks"}}, provider.Parents)
+	r.Equal("https://github.com/chanzuckerberg/infra/tree/main/stacks", util.PathURL(provider.GitOpsReference))
+}
+
+func TestModuleRefResources(t *testing.T) {
+	r := require.New(t)
+	declaration := func(repo, file string, line int) types.GitOpsReference {
+		return types.GitOpsReference{Repo: repo, Branch: "main", Path: filepath.Dir(file), File: file, StartLine: line, EndLine: line}
+	}
+	module := "github.com/chanzuckerberg/cztack//aws-vpc"
+	declarations := map[string]map[string][]types.GitOpsReference{
+		module + "?ref=v0.60.0": {"infra": {declaration("infra", "envs/dev/main.tf", 6)}},
+		module + "?ref=v0.40.0": {
+			"infra": {declaration("infra", "envs/prod/main.tf", 6), declaration("infra", "envs/staging/main.tf", 12)},
+			"apps":  {declaration("apps", "main.tf", 3)},
+		},
+	}
+	refs := []ModuleRef{
+		{Ref: "v0.60.0", Timestamp: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{Ref: "v0.40.0", Timestamp: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	resources := moduleRefResources("chanzuckerberg", module, refs, declarations, map[string]string{"infra": "platform"})
+	r.Len(resources, 4)
+	locations := []string{}
+	for _, item := range resources {
+		resource := item.GetVersionedResource()
+		r.Equal("cztack//aws-vpc", resource.ID)
+		r.Equal("v0.60.0", resource.CurrentVersion)
+		locations = append(locations, fmt.Sprintf("%s %s:%d %s %s", resource.Version, resource.GitOpsReference.File, resource.GitOpsReference.StartLine,
+			util.FormatParents(resource.Parents), resource.EOL.Status))
+	}
+	r.Equal([]string{
+		"v0.60.0 envs/dev/main.tf:6 github-repo:infra,git-path:envs/dev VALID",
+		"v0.40.0 main.tf:3 github-repo:apps,git-path:. WARNING",
+		"v0.40.0 envs/prod/main.tf:6 github-repo:infra,git-path:envs/prod WARNING",
+		"v0.40.0 envs/staging/main.tf:12 github-repo:infra,git-path:envs/staging WARNING",
+	}, locations)
+	r.Equal("platform", resources[0].GetVersionedResource().Owner)
+
+	// Each declaration is its own SARIF result
+	sarif := util.ReportToSarif(types.InventoryReport{Resources: resources})
+	r.Len(sarif.Runs[0].Results, 3)
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/eks"
 	"github.com/aws/aws-sdk-go-v2/service/lambda"
 	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
 	"k8s.io/client-go/rest"
 )
 
@@ -22,11 +23,14 @@ type AWSClient interface {
 	ListEKSAddons(cluster string) (*eks.ListAddonsOutput, error)
 	DescribeEKSClusterAddon(cluster, addon string) (*eks.DescribeAddonOutput, error)
 	ListLambdaFunctions() (*lambda.ListFunctionsOutput, error)
+	ListLambdaTags(arn string) (map[string]string, error)
 	DescribeRDSClusters() (*rds.DescribeDBClustersOutput, error)
+	DescribeRDSEngineVersions(engine string) ([]rdstypes.DBEngineVersion, error)
 	GetEKSConfig(ctx context.Context, clusterInfo *eks.DescribeClusterOutput) (*rest.Config, error)
 	GetEKSNamespaces(ctx context.Context, config *rest.Config) ([]string, error)
 	ListEC2Instances() ([]ec2types.Instance, error)
 	DescribeAMIs(imageIds []string) ([]ec2types.Image, error)
 	ListVolumes() ([]ec2types.Volume, error)
 	ListACMCertificates() ([]acmtypes.CertificateSummary, error)
+	ListACMCertificateTags(arn string) ([]acmtypes.Tag, error)
 }
@@ -7,10 +7,12 @@ import (
 
 	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
 	"github.com/chanzuckerberg/camelot/pkg/util"
+	"github.com/hashicorp/go-tfe"
 	"github.com/hashicorp/go-version"
 )
 
 func Scrape(ctx context.Context) (*types.InventoryReport, error) {
+	start := time.Now()
 	report := &types.InventoryReport{}
 
 	tfe_manager, err := Setup(ctx)
@@ -29,15 +31,12 @@ func Scrape(ctx context.Context) (*types.InventoryReport, error) {
 	}
 
 	tfcWorkspaces := []types.TfcWorkspace{}
+	workspaceOwners := map[string]string{}
 
 	for org, workspaces := range orgWorkspaces {
 		for _, workspace := range workspaces {
 			eolDate := workspace.UpdatedAt.AddDate(0, 3, 0)
-
-			var status types.Status = types.StatusValid
-			if time.Now().After(eolDate) {
-				status = types.StatusWarning
-			}
+			remainingDays := util.RemainingDays(eolDate)
 
 			resource := types.TfcWorkspace{
 				VersionedResource: types.VersionedResource{
@@ -48,10 +47,11 @@ func Scrape(ctx context.Context) (*types.InventoryReport, error) {
 					GitOpsReference: types.GitOpsReference{
 						Path: workspace.WorkingDirectory,
 					},
+					Owner: workspaceOwner(workspace),
 					EOL: types.EOLStatus{
 						EOLDate:       eolDate.Format("2006-01-02"),
-						RemainingDays: util.RemainingDays(eolDate),
-						Status:        status,
+						RemainingDays: remainingDays,
+						Status:        util.GetPolicy().Status(types.KindTFCWorkspace, remainingDays),
 					},
 				},
 			}
@@ -62,6 +62,7 @@ func Scrape(ctx context.Context) (*types.InventoryReport, error) {
 			}
 
 			tfcWorkspaces = append(tfcWorkspaces, resource)
+			workspaceOwners[org+"/"+workspace.Name] = resource.Owner
 		}
 	}
 
@@ -70,7 +71,7 @@ func Scrape(ctx context.Context) (*types.InventoryReport, error) {
 		for i, tfcWorkspace := range tfcWorkspaces {
 			tfcWorkspaces[i].CurrentVersion = mostPopularVersion.String()
 			v, err := version.NewVersion(tfcWorkspace.Version)
-			if err == nil && v.LessThan(mostPopularVersion) {
+			if err == nil && v.LessThan(mostPopularVersion) && tfcWorkspace.EOL.Status == types.StatusValid {
 				tfcWorkspaces[i].EOL.Status = types.StatusWarning
 			}
 		}
@@ -99,12 +100,15 @@ func Scrape(ctx context.Context) (*types.InventoryReport, error) {
 								VersionedResource: types.VersionedResource{
 									ID:      asset.ARN.Service + ":" + asset.ARN.Resource,
 									Kind:    types.KindTFCResource,
+									Arn:     asset.ARN.String(),
 									Parents: parents,
 									GitOpsReference: types.GitOpsReference{
-										Repo:   repoUrl,
-										Branch: branch,
-										Path:   repo.WorkingDir[0],
+										Repo:      repoUrl,
+										Branch:    branch,
+										Path:      repo.WorkingDir[0],
+										Workspace: orgName + "/" + workspace,
 									},
+									Owner: workspaceOwners[orgName+"/"+workspace],
 									EOL: types.EOLStatus{
 										Status: status,
 									},
@@ -117,5 +121,27 @@ func Scrape(ctx context.Context) (*types.InventoryReport, error) {
 		}
 	}
 
+	report.Errors = tfe_manager.Errors()
+	report.Stats = []types.ScrapeStat{{
+		Source:    "tfc",
+		Duration:  time.Since(start).Seconds(),
+		Resources: len(report.Resources),
+		Errors:    len(report.Errors),
+	}}
+
 	return report, nil
 }
+
+// defaultProject is the project workspaces land in unless they are assigned one, it tells nothing about the owner
+const defaultProject = "Default Project"
+
+// workspaceOwner is read from an owner tag of the workspace (e.g. team:payments), or else its project
+func workspaceOwner(workspace *tfe.Workspace) string {
+	if owner := util.OwnerFromTags(workspace.TagNames); len(owner) > 0 {
+		return owner
+	}
+	if workspace.Project != nil && workspace.Project.Name != defaultProject {
+		return workspace.Project.Name
+	}
+	return ""
+}
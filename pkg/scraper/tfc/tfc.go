@@ -11,6 +11,8 @@ import (
 	"sync"
 
 	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+	"github.com/chanzuckerberg/camelot/pkg/util"
 	"github.com/hashicorp/go-tfe"
 	cmap "github.com/orcaman/concurrent-map/v2"
 	"github.com/sirupsen/logrus"
@@ -19,6 +21,26 @@ import (
 type TFEAssets struct {
 	ctx    context.Context
 	client *tfe.Client
+
+	errorsMutex sync.Mutex
+	errors      []types.ScrapeError
+}
+
+func (c *TFEAssets) recordError(resource types.ParentResource, err error) {
+	c.errorsMutex.Lock()
+	defer c.errorsMutex.Unlock()
+	c.errors = append(c.errors, types.ScrapeError{
+		Source:   "tfc",
+		Resource: &resource,
+		Message:  err.Error(),
+	})
+}
+
+// Errors returns the organizations and workspaces which could not be read
+func (c *TFEAssets) Errors() []types.ScrapeError {
+	c.errorsMutex.Lock()
+	defer c.errorsMutex.Unlock()
+	return append([]types.ScrapeError{}, c.errors...)
 }
 
 type Repo struct {
@@ -117,9 +139,7 @@ func (c *TFEAssets) GetWorkspaceState(ctx context.Context, workspace *tfe.Worksp
 	req.Header.Set("Authorization", bearer)
 	req.Header.Add("Accept", "application/json")
 
-	client := &http.Client{}
-
-	response, err := client.Do(req)
+	response, err := util.HTTPClient().Do(req)
 	if err != nil {
 		return nil, 0, fmt.Errorf("error reading state for workspace '%s': %w", workspace.Name, err)
 	}
@@ -209,6 +229,7 @@ func (c *TFEAssets) GetAllWorkspaceStates(ctx context.Context, orgWorkspaces map
 				}
 
 				logrus.Debugf("error getting workspace state for workspace %s: %s", w.Name, err.Error())
+				c.recordError(types.ParentResource{Kind: types.KindTFCWorkspace, ID: org + "/" + w.Name}, err)
 			}(w, orgName, w.ID)
 		}
 
@@ -231,13 +252,14 @@ func (c *TFEAssets) AllWorkspacesByOrg(ctx context.Context, orgs map[string]*tfe
 			defer wg.Done()
 			opts := tfe.WorkspaceListOptions{
 				ListOptions: tfe.ListOptions{PageNumber: 1, PageSize: 100},
-				Include:     []tfe.WSIncludeOpt{tfe.WSOrganization, tfe.WSCurrentRun},
+				Include:     []tfe.WSIncludeOpt{tfe.WSOrganization, tfe.WSCurrentRun, tfe.WSProject},
 			}
 			items := []*tfe.Workspace{}
 			for {
 				workspace, err := c.client.Workspaces.List(ctx, org.Name, &opts)
 				if err != nil {
 					logrus.Debugf("error getting workspaces for org %s: %s", org.Name, err.Error())
+					c.recordError(types.ParentResource{Kind: types.KindTFCOrg, ID: org.Name}, fmt.Errorf("error getting workspaces: %w", err))
 					return
 				}
 				items = append(items, workspace.Items...)
@@ -312,7 +334,9 @@ func (c *TFEAssets) GetAllManagedAssets(orgWorkspaces map[string][]*tfe.Workspac
 // TFE_TOKEN=<secret>
 // TFE_ADDRESS=https://<tfe-or-tfc-url>/
 func Setup(ctx context.Context) (*TFEAssets, error) {
-	client, err := tfe.NewClient(tfe.DefaultConfig())
+	config := tfe.DefaultConfig()
+	config.HTTPClient = util.HTTPClient()
+	client, err := tfe.NewClient(config)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,109 @@
+package types
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+)
+
+// ReportSchemaVersion identifies the JSON encoding of an InventoryReport. Resources are discriminated
+// by their `kind` field.
+const ReportSchemaVersion = "camelot.report/v1"
+
+// ReportSchema is the JSON Schema of the report encoding
+//
+//go:embed schema/report.v1.json
+var ReportSchema []byte
+
+type inventoryReport InventoryReport
+
+func (r InventoryReport) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		SchemaVersion string `json:"schema_version"`
+		inventoryReport
+	}{
+		SchemaVersion:   ReportSchemaVersion,
+		inventoryReport: inventoryReport(r),
+	})
+}
+
+func (r *InventoryReport) UnmarshalJSON(b []byte) error {
+	encoded := struct {
+		SchemaVersion string            `json:"schema_version"`
+		Identity      Indentity         `json:"identity"`
+		Resources     []json.RawMessage `json:"resources"`
+		Errors        []ScrapeError     `json:"errors"`
+		Stats         []ScrapeStat      `json:"stats"`
+	}{}
+	err := json.Unmarshal(b, &encoded)
+	if err != nil {
+		return err
+	}
+
+	// Reports written before the encoding was versioned have the same shape
+	if encoded.SchemaVersion != "" && encoded.SchemaVersion != ReportSchemaVersion {
+		return fmt.Errorf("unsupported report schema version %s", encoded.SchemaVersion)
+	}
+
+	resources := make([]Versioned, 0, len(encoded.Resources))
+	for i, raw := range encoded.Resources {
+		resource, err := decodeVersioned(raw)
+		if err != nil {
+			return fmt.Errorf("unable to decode resource %d: %w", i, err)
+		}
+		resources = append(resources, resource)
+	}
+
+	r.Identity = encoded.Identity
+	r.Resources = resources
+	r.Errors = encoded.Errors
+	r.Stats = encoded.Stats
+	return nil
+}
+
+func decodeVersioned(raw json.RawMessage) (Versioned, error) {
+	header := struct {
+		Kind ResourceKind `json:"kind"`
+	}{}
+	err := json.Unmarshal(raw, &header)
+	if err != nil {
+		return nil, err
+	}
+
+	switch header.Kind {
+	case KindEKSCluster:
+		return decode[EKSCluster](raw)
+	case KindRDSCluster:
+		return decode[RDSCluster](raw)
+	case KindLambda:
+		return decode[Lambda](raw)
+	case KindVolume:
+		return decode[Volume](raw)
+	case KindACMCertificate:
+		return decode[ACMCertificate](raw)
+	case KindGithubRepo:
+		return decode[GitRepo](raw)
+	case KindTerrfaormModule:
+		return decode[TerraformModule](raw)
+	case KindHelmRelease:
+		return decode[HelmRelease](raw)
+	case KindMachineImage:
+		return decode[MachineImage](raw)
+	case KindTFCResource:
+		return decode[TfcResource](raw)
+	case KindTFCWorkspace:
+		return decode[TfcWorkspace](raw)
+	case KindTFCProvider:
+		return decode[TfcProvider](raw)
+	}
+	return nil, fmt.Errorf("unknown resource kind %q", header.Kind)
+}
+
+func decode[T Versioned](raw json.RawMessage) (Versioned, error) {
+	var resource T
+	err := json.Unmarshal(raw, &resource)
+	if err != nil {
+		return nil, err
+	}
+	return resource, nil
+}
@@ -0,0 +1,124 @@
+package types
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var sampleResources = []Versioned{
+	EKSCluster{
+		VersionedResource: VersionedResource{
+			Kind:    KindEKSCluster,
+			ID:      "cluster1",
+			Arn:     "arn:aws:eks:us-west-2:123456789012:cluster/cluster1",
+			Parents: []ParentResource{{Kind: KindAWSAccount, ID: "123456789012"}},
+			Version: "1.27",
+			EOL:     EOLStatus{EOLDate: "2024-07-24", SupportDate: "2024-07-24", Status: StatusCritical},
+		},
+		PlatformVersion: "eks.1",
+		Addons:          []EKSClusterAddon{{Name: "vpc-cni", Version: "v1.12.6-eksbuild.2", Status: "ACTIVE"}},
+	},
+	RDSCluster{VersionedResource: VersionedResource{Kind: KindRDSCluster, ID: "db1"}, Engine: "aurora-postgresql"},
+	Lambda{VersionedResource: VersionedResource{Kind: KindLambda, ID: "fn1"}, Engine: "python3.8"},
+	Volume{VersionedResource: VersionedResource{Kind: KindVolume, ID: "vol-1"}, VolumeType: "gp3", Size: 100},
+	ACMCertificate{VersionedResource: VersionedResource{Kind: KindACMCertificate, ID: "cert1"}, InUse: true, DomainName: "example.com", AlternativeNames: []string{"www.example.com"}},
+	GitRepo{VersionedResource: VersionedResource{Kind: KindGithubRepo, ID: "repo1"}},
+	TerraformModule{VersionedResource: VersionedResource{Kind: KindTerrfaormModule, ID: "module1"}},
+	HelmRelease{VersionedResource: VersionedResource{Kind: KindHelmRelease, ID: "default/release1"}, Chart: "ingress-nginx"},
+	MachineImage{VersionedResource: VersionedResource{Kind: KindMachineImage, ID: "ami-1"}},
+	TfcResource{VersionedResource: VersionedResource{Kind: KindTFCResource, ID: "eks:cluster/cluster1"}},
+	TfcWorkspace{VersionedResource: VersionedResource{Kind: KindTFCWorkspace, ID: "workspace1", GitOpsReference: GitOpsReference{Repo: "org/repo", Branch: "main", Path: "envs/prod"}}},
+	TfcProvider{VersionedResource: VersionedResource{Kind: KindTFCProvider, ID: "hashicorp/aws"}},
+}
+
+func TestReportRoundTrip(t *testing.T) {
+	r := require.New(t)
+
+	report := InventoryReport{
+		Identity:  Indentity{AwsAccountNumber: "123456789012"},
+		Resources: sampleResources,
+		Errors:    []ScrapeError{{Source: "aws", Region: "us-east-1", Resource: &ParentResource{Kind: KindEKSCluster, ID: "cluster2"}, Message: "access denied"}},
+		Stats:     []ScrapeStat{{Source: "aws", Extractor: "eks", Account: "123456789012", Region: "us-east-1", Duration: 1.5, Resources: 2, Errors: 1}},
+	}
+
+	b, err := json.Marshal(report)
+	r.NoError(err)
+	r.Contains(string(b), `"schema_version":"camelot.report/v1"`)
+
+	decoded := InventoryReport{}
+	err = json.Unmarshal(b, &decoded)
+	r.NoError(err)
+	r.Equal(report, decoded)
+
+	// Pointers encode the same way
+	b2, err := json.Marshal(&report)
+	r.NoError(err)
+	r.Equal(b, b2)
+}
+
+func TestReportUnmarshalErrors(t *testing.T) {
+	r := require.New(t)
+
+	report := InventoryReport{}
+	r.Error(json.Unmarshal([]byte(`{"schema_version":"camelot.report/v99"}`), &report))
+	r.Error(json.Unmarshal([]byte(`{"resources":[{"kind":"unknown","id":"x"}]}`), &report))
+
+	// Reports written before the encoding was versioned are accepted
+	r.NoError(json.Unmarshal([]byte(`{"resources":[{"kind":"vol","id":"vol-1","size":10}]}`), &report))
+	r.Equal(int32(10), report.Resources[0].(Volume).Size)
+}
+
+// The published schema has to describe every field of every resource kind
+func TestReportSchema(t *testing.T) {
+	r := require.New(t)
+
+	schema := struct {
+		Defs map[string]struct {
+			Properties map[string]interface{} `json:"properties"`
+		} `json:"$defs"`
+	}{}
+	r.NoError(json.Unmarshal(ReportSchema, &schema))
+
+	covers := func(def string, value interface{}, extra ...string) {
+		properties := map[string]bool{}
+		for _, d := range append([]string{def}, extra...) {
+			s, ok := schema.Defs[d]
+			r.True(ok, "schema is missing definition %s", d)
+			for name := range s.Properties {
+				properties[name] = true
+			}
+		}
+		for _, field := range jsonFields(reflect.TypeOf(value)) {
+			r.True(properties[field], "schema definition %s is missing field %s", def, field)
+		}
+	}
+
+	for _, resource := range sampleResources {
+		covers(string(resource.GetVersionedResource().Kind), resource, "versionedResource")
+	}
+	covers("eolStatus", EOLStatus{})
+	covers("gitopsReference", GitOpsReference{})
+	covers("parentResource", ParentResource{})
+	covers("scrapeError", ScrapeError{})
+	covers("scrapeStat", ScrapeStat{})
+}
+
+func jsonFields(t reflect.Type) []string {
+	fields := []string{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous {
+			fields = append(fields, jsonFields(field.Type)...)
+			continue
+		}
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name != "" && name != "-" {
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}
@@ -26,20 +26,34 @@ const KindTFCWorkspace ResourceKind = "tfc-workspace"
 const KindTFCResource ResourceKind = "tfc-resource"
 const KindTFCProvider ResourceKind = "tfc-provider"
 
+// ResourceKinds are the kinds of the resources of a report, the other kinds only appear as their parents
+var ResourceKinds = []ResourceKind{
+	KindEKSCluster, KindHelmRelease, KindRDSCluster, KindLambda, KindVolume, KindACMCertificate, KindMachineImage,
+	KindGithubRepo, KindTerrfaormModule, KindTFCWorkspace, KindTFCResource, KindTFCProvider,
+}
+
 type Versioned interface {
 	GetVersionedResource() VersionedResource
 }
 
 type EOLStatus struct {
-	EOLDate       string `json:"eol_date,omitempty"`
-	RemainingDays int    `json:"remaining_active_days"`
-	Status        Status `json:"status,omitempty"`
+	EOLDate             string `json:"eol_date,omitempty"`     // End of security support
+	SupportDate         string `json:"support_date,omitempty"` // End of active (bug-fix) support
+	ExtendedSupportDate string `json:"extended_support_date,omitempty"`
+	LTS                 bool   `json:"lts,omitempty"`
+	RemainingDays       int    `json:"remaining_active_days"`
+	Status              Status `json:"status,omitempty"`
 }
 
 type GitOpsReference struct {
-	Repo   string `json:"repo,omitempty"`
-	Branch string `json:"branch,omitempty"`
-	Path   string `json:"path,omitempty"`
+	Repo      string `json:"repo,omitempty"`
+	Branch    string `json:"branch,omitempty"`
+	Path      string `json:"path,omitempty"`
+	Workspace string `json:"workspace,omitempty"` // org/name of the TFC workspace managing the resource
+	// File, StartLine and EndLine locate the block declaring the resource, relative to the repo root
+	File      string `json:"file,omitempty"`
+	StartLine int    `json:"start_line,omitempty"`
+	EndLine   int    `json:"end_line,omitempty"`
 }
 
 type ParentResource struct {
@@ -48,14 +62,24 @@ type ParentResource struct {
 }
 
 type VersionedResource struct {
-	Kind            ResourceKind     `json:"kind,omitempty"`
-	ID              string           `json:"id,omitempty"`
-	Arn             string           `json:"arn,omitempty"`
-	Parents         []ParentResource `json:"parents,omitempty"`
-	Version         string           `json:"version,omitempty"`
-	CurrentVersion  string           `json:"current_version,omitempty"`
-	GitOpsReference GitOpsReference  `json:"gitops_reference,omitempty"`
-	EOL             EOLStatus        `json:"eol,omitempty"`
+	Kind            ResourceKind      `json:"kind,omitempty"`
+	ID              string            `json:"id,omitempty"`
+	Arn             string            `json:"arn,omitempty"`
+	Parents         []ParentResource  `json:"parents,omitempty"`
+	Version         string            `json:"version,omitempty"`
+	CurrentVersion  string            `json:"current_version,omitempty"`
+	GitOpsReference GitOpsReference   `json:"gitops_reference,omitempty"`
+	EOL             EOLStatus         `json:"eol,omitempty"`
+	Labels          map[string]string `json:"labels,omitempty"`    // AWS tags
+	Owner           string            `json:"owner,omitempty"`     // owning team
+	Unmanaged       bool              `json:"unmanaged,omitempty"` // not in the state of any TFC workspace
+	Remediation     *Remediation      `json:"remediation,omitempty"`
+}
+
+// Remediation is the recommended upgrade of a resource
+type Remediation struct {
+	TargetVersion string   `json:"target_version,omitempty"`
+	Path          []string `json:"path,omitempty"` // the versions to upgrade through, ending with the target
 }
 
 type EKSCluster struct {
@@ -134,6 +158,7 @@ func (r TerraformModule) GetVersionedResource() VersionedResource {
 
 type HelmRelease struct {
 	VersionedResource
+	Chart string `json:"chart,omitempty"`
 }
 
 func (r HelmRelease) GetVersionedResource() VersionedResource {
@@ -176,9 +201,33 @@ type Indentity struct {
 	AwsAccountNumber string `json:"aws_account_number,omitempty"`
 }
 
+// ScrapeError records a source, account, region or resource which could not be (fully) scraped
+type ScrapeError struct {
+	Source    string          `json:"source,omitempty"` // aws, github or tfc
+	Extractor string          `json:"extractor,omitempty"`
+	Account   string          `json:"account,omitempty"`
+	Region    string          `json:"region,omitempty"`
+	Resource  *ParentResource `json:"resource,omitempty"`
+	Message   string          `json:"message"`
+}
+
+// ScrapeStat records how long a source (and extractor, account and region) took to scrape, and what
+// it found
+type ScrapeStat struct {
+	Source    string  `json:"source"`
+	Extractor string  `json:"extractor,omitempty"`
+	Account   string  `json:"account,omitempty"`
+	Region    string  `json:"region,omitempty"`
+	Duration  float64 `json:"duration_seconds"`
+	Resources int     `json:"resources"`
+	Errors    int     `json:"errors"`
+}
+
 type InventoryReport struct {
-	Identity  Indentity   `json:"identity,omitempty"`
-	Resources []Versioned `json:"resources,omitempty"`
+	Identity  Indentity     `json:"identity,omitempty"`
+	Resources []Versioned   `json:"resources,omitempty"`
+	Errors    []ScrapeError `json:"errors,omitempty"`
+	Stats     []ScrapeStat  `json:"stats,omitempty"`
 	// EksClusters   []EKSCluster        `json:"eks_clusters,omitempty"`
 	// RdsClusters   []RDSCluster        `json:"rds_clusters,omitempty"`
 	// Lambdas       []Lambda            `json:"lambdas,omitempty"`
@@ -190,13 +239,19 @@ type InventoryReport struct {
 	// TfcWorkspaces []TfcWorkspace      `json:"tfc_workspace,omitempty"`
 }
 
+// Complete tells whether everything was scraped, or the report is missing some resources
+func (r InventoryReport) Complete() bool {
+	return len(r.Errors) == 0
+}
+
 type ProductCycle struct {
-	Cycle             string      `json:"cycle"`
-	ReleaseDate       string      `json:"releaseDate"`
-	Support           interface{} `json:"support"` // Could be a bool or a date string: https://endoflife.date/api/nodejs.json
-	EOL               interface{} `json:"eol"`     // Could be a bool or a date string
-	Latest            string      `json:"latest"`
-	LatestReleaseDate string      `json:"latestReleaseDate"`
-	Link              string      `json:"link,omitempty"`
-	LTS               interface{} `json:"lts"` // Could be a bool or a date string
+	Cycle             string      `json:"cycle" yaml:"cycle"`
+	ReleaseDate       string      `json:"releaseDate" yaml:"releaseDate"`
+	Support           interface{} `json:"support" yaml:"support"` // Could be a bool or a date string: https://endoflife.date/api/nodejs.json
+	EOL               interface{} `json:"eol" yaml:"eol"`         // Could be a bool or a date string
+	Latest            string      `json:"latest" yaml:"latest"`
+	LatestReleaseDate string      `json:"latestReleaseDate" yaml:"latestReleaseDate"`
+	Link              string      `json:"link,omitempty" yaml:"link,omitempty"`
+	LTS               interface{} `json:"lts" yaml:"lts"`                         // Could be a bool or a date string
+	ExtendedSupport   interface{} `json:"extendedSupport" yaml:"extendedSupport"` // Could be a bool or a date string
 }
@@ -0,0 +1,53 @@
+package types
+
+// SetVersionedResource returns a copy of item with its VersionedResource replaced
+func SetVersionedResource(item Versioned, resource VersionedResource) Versioned {
+	switch r := item.(type) {
+	case EKSCluster:
+		r.VersionedResource = resource
+		return r
+	case RDSCluster:
+		r.VersionedResource = resource
+		return r
+	case Lambda:
+		r.VersionedResource = resource
+		return r
+	case Volume:
+		r.VersionedResource = resource
+		return r
+	case ACMCertificate:
+		r.VersionedResource = resource
+		return r
+	case GitRepo:
+		r.VersionedResource = resource
+		return r
+	case TerraformModule:
+		r.VersionedResource = resource
+		return r
+	case HelmRelease:
+		r.VersionedResource = resource
+		return r
+	case MachineImage:
+		r.VersionedResource = resource
+		return r
+	case TfcResource:
+		r.VersionedResource = resource
+		return r
+	case TfcWorkspace:
+		r.VersionedResource = resource
+		return r
+	case TfcProvider:
+		r.VersionedResource = resource
+		return r
+	}
+	return item
+}
+
+// UpdateResources applies update to the VersionedResource of every resource in the report
+func (r *InventoryReport) UpdateResources(update func(resource *VersionedResource)) {
+	for i, item := range r.Resources {
+		resource := item.GetVersionedResource()
+		update(&resource)
+		r.Resources[i] = SetVersionedResource(item, resource)
+	}
+}
@@ -0,0 +1,24 @@
+package types
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateResources(t *testing.T) {
+	r := require.New(t)
+
+	report := InventoryReport{Resources: append([]Versioned{}, sampleResources...)}
+	report.UpdateResources(func(resource *VersionedResource) {
+		resource.Owner = "payments"
+	})
+
+	r.Len(report.Resources, len(sampleResources))
+	for i, item := range report.Resources {
+		r.Equal(reflect.TypeOf(sampleResources[i]), reflect.TypeOf(item))
+		r.Equal("payments", item.GetVersionedResource().Owner, "owner of %s", item.GetVersionedResource().Kind)
+		r.Empty(sampleResources[i].GetVersionedResource().Owner)
+	}
+}
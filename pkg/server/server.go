@@ -0,0 +1,289 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/chanzuckerberg/camelot/pkg/history"
+	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+	"github.com/chanzuckerberg/camelot/pkg/util"
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultInterval is the time between two scrapes
+const DefaultInterval = 6 * time.Hour
+
+// ScrapeFunc scrapes the inventory served
+type ScrapeFunc func(ctx context.Context) (*types.InventoryReport, error)
+
+// Server scrapes the inventory on a schedule and serves the latest report over HTTP
+type Server struct {
+	scrape    ScrapeFunc
+	interval  time.Duration
+	cacheFile string
+	now       func() time.Time
+
+	mu        sync.RWMutex
+	report    *types.InventoryReport
+	scrapedAt time.Time
+	lastErr   error
+}
+
+type ServerOpt func(*Server)
+
+// WithInterval sets the time between two scrapes
+func WithInterval(interval time.Duration) ServerOpt {
+	return func(s *Server) {
+		s.interval = interval
+	}
+}
+
+// WithCacheFile keeps the latest report in a file, so that a restarted server serves it right away and
+// only scrapes again when it is older than the interval
+func WithCacheFile(path string) ServerOpt {
+	return func(s *Server) {
+		s.cacheFile = path
+	}
+}
+
+func NewServer(scrape ScrapeFunc, opts ...ServerOpt) *Server {
+	s := &Server{scrape: scrape, interval: DefaultInterval, now: time.Now}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Run scrapes every interval until the context is done
+func (s *Server) Run(ctx context.Context) {
+	next := time.Duration(0)
+	if len(s.cacheFile) > 0 {
+		err := s.loadCache()
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			logrus.Warnf("unable to load the cached inventory: %s", err.Error())
+		}
+		if _, scrapedAt := s.Report(); !scrapedAt.IsZero() {
+			next = max(0, scrapedAt.Add(s.interval).Sub(s.now()))
+		}
+	}
+
+	timer := time.NewTimer(next)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+			err := s.Refresh(ctx)
+			if err != nil {
+				logrus.Errorf("failed to scrape the inventory: %s", err.Error())
+			}
+			timer.Reset(s.interval)
+		}
+	}
+}
+
+// Refresh scrapes the inventory once. The previous report is kept when the scrape fails, or when it
+// only has scrape errors, like when the credentials of every account expired.
+func (s *Server) Refresh(ctx context.Context) error {
+	start := s.now()
+	report, err := s.scrape(ctx)
+	if err == nil && report == nil {
+		err = fmt.Errorf("no report was produced")
+	}
+	if err == nil && len(report.Resources) == 0 && len(report.Errors) > 0 {
+		failures := []error{}
+		for _, e := range report.Errors {
+			failures = append(failures, errors.New(e.Message))
+		}
+		err = fmt.Errorf("no resources were scraped: %w", errors.Join(failures...))
+	}
+
+	s.mu.Lock()
+	s.lastErr = err
+	if err == nil {
+		s.report = report
+		s.scrapedAt = start
+	}
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	logrus.Infof("scraped %d resources in %s", len(report.Resources), s.now().Sub(start).Round(time.Second))
+	if len(s.cacheFile) > 0 {
+		err = s.saveCache(history.Snapshot{RecordedAt: start, Source: "serve", Report: report})
+		if err != nil {
+			logrus.Warnf("unable to cache the inventory: %s", err.Error())
+		}
+	}
+	return nil
+}
+
+// Report returns the latest report and when it was scraped, or nil before the first scrape
+func (s *Server) Report() (*types.InventoryReport, time.Time) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.report, s.scrapedAt
+}
+
+func (s *Server) loadCache() error {
+	b, err := os.ReadFile(s.cacheFile)
+	if err != nil {
+		return err
+	}
+	snapshot := history.Snapshot{}
+	err = json.Unmarshal(b, &snapshot)
+	if err != nil {
+		return fmt.Errorf("unable to parse %s: %w", s.cacheFile, err)
+	}
+	if snapshot.Report == nil {
+		return fmt.Errorf("%s has no report", s.cacheFile)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.report = snapshot.Report
+	s.scrapedAt = snapshot.RecordedAt
+	return nil
+}
+
+func (s *Server) saveCache(snapshot history.Snapshot) error {
+	b, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("unable to marshal the inventory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.cacheFile), ".camelot-cache-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(b)
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.cacheFile)
+}
+
+// Handler serves /healthz, /metrics, /api/v1/resources and /api/v1/summary
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", s.healthz)
+	mux.HandleFunc("GET /metrics", s.metrics)
+	mux.HandleFunc("GET /api/v1/resources", s.resources)
+	mux.HandleFunc("GET /api/v1/summary", s.summary)
+	return mux
+}
+
+// healthz is healthy once a report is available, even when the last scrape failed
+func (s *Server) healthz(w http.ResponseWriter, r *http.Request) {
+	report, scrapedAt := s.Report()
+	if report == nil {
+		http.Error(w, "no inventory was scraped yet", http.StatusServiceUnavailable)
+		return
+	}
+	s.mu.RLock()
+	lastErr := s.lastErr
+	s.mu.RUnlock()
+
+	status := fmt.Sprintf("ok, scraped at %s\n", scrapedAt.UTC().Format(time.RFC3339))
+	if lastErr != nil {
+		status += fmt.Sprintf("the last scrape failed: %s\n", lastErr.Error())
+	}
+	_, _ = w.Write([]byte(status))
+}
+
+func (s *Server) metrics(w http.ResponseWriter, r *http.Request) {
+	report, scrapedAt := s.Report()
+	if report == nil {
+		http.Error(w, "no inventory was scraped yet", http.StatusServiceUnavailable)
+		return
+	}
+	s.mu.RLock()
+	success := 1
+	if s.lastErr != nil {
+		success = 0
+	}
+	s.mu.RUnlock()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = w.Write([]byte(util.ReportToPrometheus(*report)))
+	_, _ = fmt.Fprintf(w, "# HELP camelot_last_scrape_timestamp_seconds Time of the scrape of the inventory served.\n"+
+		"# TYPE camelot_last_scrape_timestamp_seconds gauge\ncamelot_last_scrape_timestamp_seconds %d\n", scrapedAt.Unix())
+	_, _ = fmt.Fprintf(w, "# HELP camelot_last_scrape_success Whether the last scrape succeeded.\n"+
+		"# TYPE camelot_last_scrape_success gauge\ncamelot_last_scrape_success %d\n", success)
+}
+
+// filtered applies the filter query parameters, which have the syntax of -f, to the latest report
+func (s *Server) filtered(w http.ResponseWriter, r *http.Request) (*types.InventoryReport, time.Time, bool) {
+	report, scrapedAt := s.Report()
+	if report == nil {
+		http.Error(w, "no inventory was scraped yet", http.StatusServiceUnavailable)
+		return nil, scrapedAt, false
+	}
+	filter, err := util.CreateFilter(r.URL.Query()["filter"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return nil, scrapedAt, false
+	}
+	return util.FilterReport(report, filter), scrapedAt, true
+}
+
+func (s *Server) resources(w http.ResponseWriter, r *http.Request) {
+	report, _, ok := s.filtered(w, r)
+	if !ok {
+		return
+	}
+	err := util.SortReport(report, r.URL.Query()["sort_by"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, report)
+}
+
+func (s *Server) summary(w http.ResponseWriter, r *http.Request) {
+	report, scrapedAt, ok := s.filtered(w, r)
+	if !ok {
+		return
+	}
+	soonest := util.DefaultSoonest
+	if limit := r.URL.Query().Get("limit"); len(limit) > 0 {
+		var err error
+		soonest, err = strconv.Atoi(limit)
+		if err != nil || soonest < 0 {
+			http.Error(w, fmt.Sprintf("invalid limit %q", limit), http.StatusBadRequest)
+			return
+		}
+	}
+	writeJSON(w, struct {
+		ScrapedAt time.Time `json:"scraped_at"`
+		util.ReportSummary
+	}{
+		ScrapedAt:     scrapedAt,
+		ReportSummary: util.SummarizeReport(*report, s.now(), soonest),
+	})
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to marshal json: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(b)
+}
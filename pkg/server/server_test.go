@@ -0,0 +1,160 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+	"github.com/stretchr/testify/require"
+)
+
+func testReport() *types.InventoryReport {
+	account := []types.ParentResource{{Kind: types.KindAWSAccount, ID: "123"}}
+	return &types.InventoryReport{
+		Resources: []types.Versioned{
+			types.EKSCluster{VersionedResource: types.VersionedResource{Kind: types.KindEKSCluster, ID: "prod", Parents: account, Version: "1.27",
+				EOL: types.EOLStatus{EOLDate: "2024-07-24", RemainingDays: 45, Status: types.StatusCritical}}},
+			types.RDSCluster{VersionedResource: types.VersionedResource{Kind: types.KindRDSCluster, ID: "analytics", Parents: account, Version: "15.4",
+				EOL: types.EOLStatus{EOLDate: "2027-11-11", RemainingDays: 1200, Status: types.StatusValid}}},
+		},
+		Stats: []types.ScrapeStat{{Source: "aws", Extractor: "eks", Account: "123", Region: "us-west-2", Duration: 2}},
+	}
+}
+
+func get(r *require.Assertions, server *httptest.Server, path string) (int, string) {
+	res, err := http.Get(server.URL + path)
+	r.NoError(err)
+	defer res.Body.Close()
+	b, err := io.ReadAll(res.Body)
+	r.NoError(err)
+	return res.StatusCode, string(b)
+}
+
+func TestServer(t *testing.T) {
+	r := require.New(t)
+	scrapeErr := error(nil)
+	s := NewServer(func(ctx context.Context) (*types.InventoryReport, error) {
+		return testReport(), scrapeErr
+	})
+	s.now = func() time.Time { return time.Date(2024, 6, 9, 0, 0, 0, 0, time.UTC) }
+	server := httptest.NewServer(s.Handler())
+	defer server.Close()
+
+	code, _ := get(r, server, "/healthz")
+	r.Equal(http.StatusServiceUnavailable, code)
+	code, _ = get(r, server, "/metrics")
+	r.Equal(http.StatusServiceUnavailable, code)
+
+	r.NoError(s.Refresh(context.Background()))
+	code, body := get(r, server, "/healthz")
+	r.Equal(http.StatusOK, code)
+	r.Contains(body, "ok, scraped at 2024-06-09T00:00:00Z")
+
+	code, body = get(r, server, "/metrics")
+	r.Equal(http.StatusOK, code)
+	r.Contains(body, `camelot_resource_remaining_days{kind="eks",id="prod",parent="aws:123",version="1.27",account="123",owner=""} 45`)
+	r.Contains(body, "camelot_last_scrape_success 1")
+
+	code, body = get(r, server, "/api/v1/resources?filter=kind%3Drds")
+	r.Equal(http.StatusOK, code)
+	report := types.InventoryReport{}
+	r.NoError(json.Unmarshal([]byte(body), &report))
+	r.Len(report.Resources, 1)
+	r.Equal("analytics", report.Resources[0].GetVersionedResource().ID)
+
+	code, body = get(r, server, "/api/v1/resources?filter="+"eol.remaining_days+%3C+60+and+status+%3D+critical")
+	r.Equal(http.StatusOK, code)
+	r.NoError(json.Unmarshal([]byte(body), &report))
+	r.Len(report.Resources, 1)
+	r.Equal("prod", report.Resources[0].GetVersionedResource().ID)
+
+	code, _ = get(r, server, "/api/v1/resources?filter=size%3E1")
+	r.Equal(http.StatusBadRequest, code)
+
+	code, body = get(r, server, "/api/v1/summary?limit=1")
+	r.Equal(http.StatusOK, code)
+	summary := struct {
+		ScrapedAt time.Time                 `json:"scraped_at"`
+		Resources int                       `json:"resources"`
+		Kinds     map[string]map[string]int `json:"kinds"`
+		Soonest   []struct {
+			ID string `json:"id"`
+		} `json:"soonest_eol"`
+	}{}
+	r.NoError(json.Unmarshal([]byte(body), &summary))
+	r.Equal(2, summary.Resources)
+	r.Equal(map[string]int{"CRITICAL": 1}, summary.Kinds["eks"])
+	r.Len(summary.Soonest, 1)
+	r.Equal("prod", summary.Soonest[0].ID)
+
+	// A failed scrape keeps serving the previous inventory
+	scrapeErr = errors.New("expired credentials")
+	r.Error(s.Refresh(context.Background()))
+	code, body = get(r, server, "/healthz")
+	r.Equal(http.StatusOK, code)
+	r.Contains(body, "expired credentials")
+	_, body = get(r, server, "/metrics")
+	r.Contains(body, "camelot_last_scrape_success 0")
+}
+
+func TestServerKeepsReportWithoutResources(t *testing.T) {
+	r := require.New(t)
+	report := testReport()
+	s := NewServer(func(ctx context.Context) (*types.InventoryReport, error) {
+		return report, nil
+	})
+	r.NoError(s.Refresh(context.Background()))
+
+	// Every account failed: the errors are returned and the previous inventory is kept
+	report = &types.InventoryReport{Errors: []types.ScrapeError{
+		{Source: "aws", Account: "123", Message: "expired credentials"},
+		{Source: "aws", Message: "failed to load config for profile prod: no such profile"},
+	}}
+	err := s.Refresh(context.Background())
+	r.Error(err)
+	r.Contains(err.Error(), "expired credentials")
+	r.Contains(err.Error(), "failed to load config for profile prod")
+	served, _ := s.Report()
+	r.Len(served.Resources, 2)
+
+	// A partial scrape replaces it, with its errors
+	report = testReport()
+	report.Resources = report.Resources[:1]
+	report.Errors = []types.ScrapeError{{Source: "aws", Account: "456", Message: "expired credentials"}}
+	r.NoError(s.Refresh(context.Background()))
+	served, _ = s.Report()
+	r.Len(served.Resources, 1)
+	r.False(served.Complete())
+}
+
+func TestServerCache(t *testing.T) {
+	r := require.New(t)
+	cacheFile := filepath.Join(t.TempDir(), "inventory.json")
+	scrapes := 0
+	scrape := func(ctx context.Context) (*types.InventoryReport, error) {
+		scrapes++
+		return testReport(), nil
+	}
+
+	s := NewServer(scrape, WithCacheFile(cacheFile))
+	r.NoError(s.Refresh(context.Background()))
+	r.Equal(1, scrapes)
+
+	// A restarted server serves the cached inventory, and does not scrape before the interval
+	restarted := NewServer(scrape, WithCacheFile(cacheFile), WithInterval(time.Hour))
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	restarted.Run(ctx)
+	report, scrapedAt := restarted.Report()
+	r.NotNil(report)
+	r.Len(report.Resources, 2)
+	r.False(scrapedAt.IsZero())
+	r.Equal(1, scrapes)
+}
@@ -0,0 +1,137 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/chanzuckerberg/camelot/pkg/util"
+	"github.com/sirupsen/logrus"
+)
+
+// defaultS3Region signs requests to S3-compatible endpoints, like MinIO, when no region is configured
+const defaultS3Region = "us-east-1"
+
+// S3Sink uploads reports to an S3-compatible bucket, each under a new timestamped key
+type S3Sink struct {
+	bucket      string
+	prefix      string
+	file        File
+	endpoint    string
+	region      string
+	credentials aws.CredentialsProvider
+	httpClient  *http.Client
+	client      *s3.Client
+	now         func() time.Time
+}
+
+type S3Opt func(*S3Sink)
+
+// WithEndpoint sends requests to an S3-compatible endpoint (e.g. http://localhost:9000 for MinIO) with
+// path-style URLs, instead of the AWS endpoint of the region
+func WithEndpoint(endpoint string) S3Opt {
+	return func(s *S3Sink) {
+		s.endpoint = strings.TrimSuffix(endpoint, "/")
+	}
+}
+
+func WithRegion(region string) S3Opt {
+	return func(s *S3Sink) {
+		s.region = region
+	}
+}
+
+// WithCredentials signs requests with the given credentials instead of those of the AWS config
+func WithCredentials(credentials aws.CredentialsProvider) S3Opt {
+	return func(s *S3Sink) {
+		s.credentials = credentials
+	}
+}
+
+func WithHTTPClient(client *http.Client) S3Opt {
+	return func(s *S3Sink) {
+		s.httpClient = client
+	}
+}
+
+// NewS3Sink uploads to bucket. A prefix ending with / (or none) is a folder in which reports are named
+// camelot-<timestamp>.<extension>, other prefixes get the timestamp appended, e.g. nightly/aws.json is
+// uploaded as nightly/aws-20240101T000000Z.json. The region and credentials default to those of the
+// AWS config (AWS_PROFILE, AWS_REGION, ...).
+func NewS3Sink(ctx context.Context, bucket, prefix string, file File, opts ...S3Opt) (*S3Sink, error) {
+	s := &S3Sink{bucket: bucket, prefix: prefix, file: file, httpClient: util.HTTPClient(), now: time.Now}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	cfg := aws.Config{Region: s.region, Credentials: s.credentials, HTTPClient: s.httpClient}
+	if s.credentials == nil || len(s.region) == 0 {
+		loaded, err := config.LoadDefaultConfig(ctx, config.WithHTTPClient(s.httpClient))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the aws config: %w", err)
+		}
+		if s.credentials != nil {
+			loaded.Credentials = s.credentials
+		}
+		if len(s.region) > 0 {
+			loaded.Region = s.region
+		}
+		cfg = loaded
+	}
+	if len(cfg.Region) == 0 {
+		if len(s.endpoint) == 0 {
+			return nil, fmt.Errorf("no region is configured for the s3 bucket %s", bucket)
+		}
+		cfg.Region = defaultS3Region
+	}
+	s.region = cfg.Region
+
+	s.client = s3.NewFromConfig(cfg, func(o *s3.Options) {
+		if len(s.endpoint) > 0 {
+			o.BaseEndpoint = aws.String(s.endpoint)
+			o.UsePathStyle = true
+			// Not every S3-compatible storage supports the checksums AWS adds by default
+			o.RequestChecksumCalculation = aws.RequestChecksumCalculationWhenRequired
+		}
+	})
+	return s, nil
+}
+
+// Key returns the key of a report uploaded at a given time
+func (s *S3Sink) Key(at time.Time) string {
+	timestamp := at.UTC().Format("20060102T150405Z")
+	if len(s.prefix) == 0 || strings.HasSuffix(s.prefix, "/") {
+		return fmt.Sprintf("%scamelot-%s.%s", s.prefix, timestamp, s.file.Extension)
+	}
+	base := strings.TrimSuffix(s.prefix, "."+s.file.Extension)
+	return fmt.Sprintf("%s-%s.%s", base, timestamp, s.file.Extension)
+}
+
+// Write uploads the report under a new key
+func (s *S3Sink) Write(ctx context.Context, content []byte) error {
+	key := s.Key(s.now())
+	input := &s3.PutObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+		Body:   bytes.NewReader(content),
+	}
+	if len(s.file.ContentType) > 0 {
+		input.ContentType = aws.String(s.file.ContentType)
+	}
+	_, err := s.client.PutObject(ctx, input)
+	if err != nil {
+		return fmt.Errorf("failed to upload s3://%s/%s: %w", s.bucket, key, err)
+	}
+	logrus.Infof("uploaded the report to s3://%s/%s", s.bucket, key)
+	return nil
+}
+
+func (s *S3Sink) String() string {
+	return fmt.Sprintf("s3://%s/%s", s.bucket, s.prefix)
+}
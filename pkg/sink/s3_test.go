@@ -0,0 +1,220 @@
+package sink
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/stretchr/testify/require"
+)
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func hexSHA256(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// s3Escape encodes a path the way S3 canonicalizes it: every byte but the unreserved characters and /
+func s3Escape(path string) string {
+	var sb strings.Builder
+	for _, b := range []byte(path) {
+		if ('A' <= b && b <= 'Z') || ('a' <= b && b <= 'z') || ('0' <= b && b <= '9') || strings.IndexByte("-_.~/", b) >= 0 {
+			sb.WriteByte(b)
+		} else {
+			fmt.Fprintf(&sb, "%%%02X", b)
+		}
+	}
+	return sb.String()
+}
+
+// verifySigV4 checks the signature of a request the way S3 does, from the key it received rather than
+// the path as sent, so that a request signed over another encoding of the key fails
+func verifySigV4(req *http.Request, body []byte, secret string) error {
+	auth := strings.TrimPrefix(req.Header.Get("Authorization"), "AWS4-HMAC-SHA256 ")
+	fields := map[string]string{}
+	for _, field := range strings.Split(auth, ", ") {
+		k, v, _ := strings.Cut(field, "=")
+		fields[k] = v
+	}
+	scope := strings.SplitN(fields["Credential"], "/", 2)
+	if len(scope) != 2 {
+		return fmt.Errorf("invalid authorization %q", req.Header.Get("Authorization"))
+	}
+
+	payloadHash := req.Header.Get("X-Amz-Content-Sha256")
+	if payloadHash != "UNSIGNED-PAYLOAD" && payloadHash != hexSHA256(body) {
+		return fmt.Errorf("payload hash %s does not match the body", payloadHash)
+	}
+
+	query := req.URL.Query()
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	params := []string{}
+	for _, k := range keys {
+		for _, v := range query[k] {
+			params = append(params, s3Escape(k)+"="+strings.ReplaceAll(s3Escape(v), "/", "%2F"))
+		}
+	}
+
+	headers := ""
+	for _, name := range strings.Split(fields["SignedHeaders"], ";") {
+		value := req.Header.Get(name)
+		switch name {
+		case "host":
+			value = req.Host
+		case "content-length":
+			value = strconv.FormatInt(req.ContentLength, 10)
+		}
+		headers += name + ":" + strings.TrimSpace(value) + "\n"
+	}
+
+	canonical := strings.Join([]string{req.Method, s3Escape(req.URL.Path), strings.Join(params, "&"), headers, fields["SignedHeaders"], payloadHash}, "\n")
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", req.Header.Get("X-Amz-Date"), scope[1], hexSHA256([]byte(canonical))}, "\n")
+	key := []byte("AWS4" + secret)
+	for _, part := range strings.Split(scope[1], "/") {
+		key = hmacSHA256(key, part)
+	}
+	if signature := hex.EncodeToString(hmacSHA256(key, stringToSign)); signature != fields["Signature"] {
+		return fmt.Errorf("signature mismatch, canonical request:\n%s", canonical)
+	}
+	return nil
+}
+
+func TestVerifySigV4(t *testing.T) {
+	r := require.New(t)
+	sign := func(rawPath string, disableEscaping bool) *http.Request {
+		req := httptest.NewRequest(http.MethodPut, "http://localhost:9000"+rawPath, strings.NewReader("{}"))
+		req.Header.Set("X-Amz-Content-Sha256", hexSHA256([]byte("{}")))
+		err := v4.NewSigner().SignHTTP(context.Background(), aws.Credentials{AccessKeyID: "AKID", SecretAccessKey: "SECRET"}, req,
+			hexSHA256([]byte("{}")), "s3", "us-east-1", time.Now(), func(o *v4.SignerOptions) { o.DisableURIPathEscaping = disableEscaping })
+		r.NoError(err)
+		return req
+	}
+
+	r.NoError(verifySigV4(sign("/archive/dt%3D2024-06-01/a%2Bb%3Ac", true), []byte("{}"), "SECRET"))
+	// Signed over the key as sent rather than as S3 encodes it, or encoded twice
+	r.Error(verifySigV4(sign("/archive/dt=2024-06-01/a+b:c", true), []byte("{}"), "SECRET"))
+	r.Error(verifySigV4(sign("/archive/team%20reports", false), []byte("{}"), "SECRET"))
+	r.Error(verifySigV4(sign("/archive/camelot.json", true), []byte("{}"), "OTHER"))
+}
+
+func TestS3Sink(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+	creds := credentials.NewStaticCredentialsProvider("AKID", "SECRET", "")
+
+	type upload struct {
+		key, contentType, body string
+	}
+	uploads := []upload{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, err := io.ReadAll(req.Body)
+		r.NoError(err)
+		if strings.Contains(req.URL.Path, "denied") {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = io.WriteString(w, "<Error><Code>AccessDenied</Code><Message>Access Denied</Message></Error>")
+			return
+		}
+		r.Equal(http.MethodPut, req.Method)
+		r.NoError(verifySigV4(req, body, "SECRET"))
+
+		uploads = append(uploads, upload{
+			key:         req.URL.Path,
+			contentType: req.Header.Get("Content-Type"),
+			body:        string(body),
+		})
+	}))
+	defer server.Close()
+
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	newSink := func(prefix string) *S3Sink {
+		s, err := NewS3Sink(ctx, "archive", prefix, File{Extension: "json", ContentType: "application/json"},
+			WithEndpoint(server.URL+"/"), WithRegion("us-east-1"), WithCredentials(creds), WithHTTPClient(server.Client()))
+		r.NoError(err)
+		s.now = func() time.Time { return now }
+		return s
+	}
+
+	r.NoError(newSink("camelot/nightly/").Write(ctx, []byte(`{"resources":[]}`)))
+	r.NoError(newSink("camelot/aws.json").Write(ctx, []byte(`{}`)))
+	r.NoError(newSink("team reports/aws").Write(ctx, []byte(`{}`)))
+	r.NoError(newSink("dt=2024-06-01/a+b:c/").Write(ctx, []byte(`{}`)))
+	r.Equal([]upload{
+		{key: "/archive/camelot/nightly/camelot-20240102T030405Z.json", contentType: "application/json", body: `{"resources":[]}`},
+		{key: "/archive/camelot/aws-20240102T030405Z.json", contentType: "application/json", body: `{}`},
+		{key: "/archive/team reports/aws-20240102T030405Z.json", contentType: "application/json", body: `{}`},
+		{key: "/archive/dt=2024-06-01/a+b:c/camelot-20240102T030405Z.json", contentType: "application/json", body: `{}`},
+	}, uploads)
+
+	err := newSink("denied/").Write(ctx, []byte(`{}`))
+	r.ErrorContains(err, "StatusCode: 403")
+	r.ErrorContains(err, "AccessDenied")
+}
+
+func TestS3SinkAWSEndpoint(t *testing.T) {
+	r := require.New(t)
+	requests := []*http.Request{}
+	client := &http.Client{Transport: roundTripper(func(req *http.Request) (*http.Response, error) {
+		requests = append(requests, req)
+		return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: io.NopCloser(strings.NewReader("")), Request: req}, nil
+	})}
+	s, err := NewS3Sink(context.Background(), "archive", "", File{Extension: "md"}, WithRegion("us-west-2"),
+		WithCredentials(credentials.NewStaticCredentialsProvider("AKID", "SECRET", "")), WithHTTPClient(client))
+	r.NoError(err)
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	s.now = func() time.Time { return at }
+	r.Equal("camelot-20240102T030405Z.md", s.Key(at))
+
+	r.NoError(s.Write(context.Background(), []byte("# Report")))
+	r.Len(requests, 1)
+	r.Equal("archive.s3.us-west-2.amazonaws.com", requests[0].URL.Host)
+	r.Equal("/camelot-20240102T030405Z.md", requests[0].URL.Path)
+}
+
+type roundTripper func(req *http.Request) (*http.Response, error)
+
+func (f roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestNew(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+
+	s, err := New(ctx, "-", File{})
+	r.NoError(err)
+	r.Equal(Stdout{}, s)
+
+	s, err = New(ctx, "out/report.json", File{})
+	r.NoError(err)
+	r.Equal(FileSink{Path: "out/report.json"}, s)
+
+	s, err = New(ctx, "s3://archive/nightly/", File{Extension: "json"}, WithRegion("us-west-2"),
+		WithCredentials(credentials.NewStaticCredentialsProvider("AKID", "SECRET", "")))
+	r.NoError(err)
+	r.Equal("s3://archive/nightly/", s.String())
+
+	_, err = New(ctx, "s3:///nightly", File{})
+	r.Error(err)
+}
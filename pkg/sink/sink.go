@@ -0,0 +1,69 @@
+package sink
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Sink stores a rendered report
+type Sink interface {
+	Write(ctx context.Context, content []byte) error
+	// String names the destination in logs and errors
+	String() string
+}
+
+// File describes the report a sink stores
+type File struct {
+	Extension   string // without the leading dot, e.g. json
+	ContentType string
+}
+
+// New opens the sink of a destination: - for stdout, s3://<bucket>/<prefix> for an S3-compatible
+// bucket, or else a file path
+func New(ctx context.Context, destination string, file File, opts ...S3Opt) (Sink, error) {
+	if destination == "-" {
+		return Stdout{}, nil
+	}
+	if location, ok := strings.CutPrefix(destination, "s3://"); ok {
+		bucket, prefix, _ := strings.Cut(location, "/")
+		if len(bucket) == 0 {
+			return nil, fmt.Errorf("invalid destination %s: no bucket", destination)
+		}
+		return NewS3Sink(ctx, bucket, prefix, file, opts...)
+	}
+	return FileSink{Path: destination}, nil
+}
+
+// Stdout writes reports to the standard output
+type Stdout struct{}
+
+func (Stdout) Write(ctx context.Context, content []byte) error {
+	_, err := os.Stdout.Write(content)
+	if err != nil {
+		return fmt.Errorf("failed to write to stdout: %w", err)
+	}
+	return nil
+}
+
+func (Stdout) String() string {
+	return "stdout"
+}
+
+// FileSink writes reports to a file, replacing it
+type FileSink struct {
+	Path string
+}
+
+func (f FileSink) Write(ctx context.Context, content []byte) error {
+	err := os.WriteFile(f.Path, content, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", f.Path, err)
+	}
+	return nil
+}
+
+func (f FileSink) String() string {
+	return f.Path
+}
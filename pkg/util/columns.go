@@ -0,0 +1,232 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+)
+
+// NoneValue is shown for fields a resource does not have
+const NoneValue = "<none>"
+
+// Column is a custom column of the text output, reading a field of the resources
+type Column struct {
+	Header string
+	Path   []string
+}
+
+// ParseColumns parses a custom columns spec, e.g. NAME:.ID,DOMAIN:.DomainName,TEAM:.Labels.team. Fields
+// are named like in Go or in JSON (.PlatformVersion or .platform_version), and reach kind-specific fields
+// which the default table does not show.
+func ParseColumns(spec string) ([]Column, error) {
+	columns := []Column{}
+	for _, definition := range strings.Split(spec, ",") {
+		header, path, ok := strings.Cut(definition, ":")
+		if !ok || len(header) == 0 {
+			return nil, fmt.Errorf("invalid custom column %q, expected <HEADER>:<FIELD>", definition)
+		}
+		path = strings.TrimPrefix(strings.TrimSpace(path), ".")
+		if len(path) == 0 {
+			return nil, fmt.Errorf("custom column %q has no field", header)
+		}
+		columns = append(columns, Column{Header: header, Path: strings.Split(path, ".")})
+	}
+	return columns, nil
+}
+
+// ColumnsToTable renders resources as table rows of the given columns
+func ColumnsToTable(report types.InventoryReport, columns []Column) [][]string {
+	var table [][]string
+	for _, item := range report.Resources {
+		row := []string{}
+		for _, column := range columns {
+			row = append(row, FieldValue(item, column.Path))
+		}
+		table = append(table, row)
+	}
+	return table
+}
+
+// FieldValue formats the field at path of a resource, or NoneValue when the resource does not have it
+func FieldValue(item types.Versioned, path []string) string {
+	v := reflect.ValueOf(item)
+	for _, name := range path {
+		var ok bool
+		v, ok = fieldByName(v, name)
+		if !ok {
+			return NoneValue
+		}
+	}
+	return formatValue(v)
+}
+
+func fieldByName(v reflect.Value, name string) (reflect.Value, bool) {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}, false
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return reflect.Value{}, false
+		}
+		value := v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key()))
+		return value, value.IsValid()
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			jsonName, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if field.IsExported() && (strings.EqualFold(field.Name, name) || jsonName == name) {
+				return v.Field(i), true
+			}
+		}
+		// Fields of embedded structs, like VersionedResource
+		for i := 0; i < t.NumField(); i++ {
+			if t.Field(i).Anonymous {
+				if value, ok := fieldByName(v.Field(i), name); ok {
+					return value, true
+				}
+			}
+		}
+	}
+	return reflect.Value{}, false
+}
+
+func formatValue(v reflect.Value) string {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return NoneValue
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() != reflect.Struct {
+			values := []string{}
+			for i := 0; i < v.Len(); i++ {
+				values = append(values, formatValue(v.Index(i)))
+			}
+			return strings.Join(values, ",")
+		}
+		return formatJSON(v)
+	case reflect.Struct, reflect.Map:
+		return formatJSON(v)
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+func formatJSON(v reflect.Value) string {
+	b, err := json.Marshal(v.Interface())
+	if err != nil {
+		return fmt.Sprint(v.Interface())
+	}
+	return string(b)
+}
+
+// recordHeader is the header of the common columns of ReportToRecords
+var recordHeader = []string{
+	"kind", "id", "parents", "arn", "version", "current_version", "status", "eol_date", "support_date", "remaining_days",
+	"owner", "gitops_repo", "gitops_branch", "gitops_path", "gitops_workspace", "upgrade_path",
+}
+
+// ReportToRecords flattens resources into untruncated records for csv and tsv output: the common columns,
+// the given tags and the kind-specific fields of the resources in the report, named <kind>.<field> and
+// empty for other kinds
+func ReportToRecords(report types.InventoryReport, tagColumns ...string) ([]string, [][]string) {
+	header := append([]string{}, recordHeader...)
+	for _, tag := range tagColumns {
+		header = append(header, "tag:"+tag)
+	}
+
+	columns := []string{}
+	seen := map[string]bool{}
+	values := make([]map[string]string, len(report.Resources))
+	for i, item := range report.Resources {
+		values[i] = map[string]string{}
+		names, fieldValues := kindSpecificFields(item)
+		for j, name := range names {
+			column := string(item.GetVersionedResource().Kind) + "." + name
+			values[i][column] = fieldValues[j]
+			if !seen[column] {
+				seen[column] = true
+				columns = append(columns, column)
+			}
+		}
+	}
+	header = append(header, columns...)
+
+	records := [][]string{}
+	for i, item := range report.Resources {
+		resource := item.GetVersionedResource()
+		record := []string{
+			string(resource.Kind),
+			resource.ID,
+			FormatParents(resource.Parents),
+			resource.Arn,
+			resource.Version,
+			resource.CurrentVersion,
+			string(resource.EOL.Status),
+			resource.EOL.EOLDate,
+			resource.EOL.SupportDate,
+			fmt.Sprint(resource.EOL.RemainingDays),
+			resource.Owner,
+			resource.GitOpsReference.Repo,
+			resource.GitOpsReference.Branch,
+			resource.GitOpsReference.Path,
+			resource.GitOpsReference.Workspace,
+			FormatUpgradePath(resource.Remediation),
+		}
+		for _, tag := range tagColumns {
+			record = append(record, resource.Labels[tag])
+		}
+		for _, column := range columns {
+			record = append(record, values[i][column])
+		}
+		records = append(records, record)
+	}
+	return header, records
+}
+
+// kindSpecificFields returns the JSON names and values of the fields a resource adds to VersionedResource
+func kindSpecificFields(item types.Versioned) ([]string, []string) {
+	names, values := []string{}, []string{}
+	v := reflect.Indirect(reflect.ValueOf(item))
+	if v.Kind() != reflect.Struct {
+		return names, values
+	}
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if field.Anonymous || !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if len(name) == 0 {
+			name = field.Name
+		}
+		names = append(names, name)
+		values = append(values, formatRecordValue(v.Field(i)))
+	}
+	return names, values
+}
+
+// formatRecordValue is formatValue, with nil pointers and collections left empty
+func formatRecordValue(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Slice, reflect.Map:
+		if v.IsNil() {
+			return ""
+		}
+	}
+	return formatValue(v)
+}
@@ -0,0 +1,58 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCustomColumns(t *testing.T) {
+	r := require.New(t)
+
+	columns, err := ParseColumns("NAME:.ID,PLATFORM:.PlatformVersion,DOMAIN:.domainname,SIZE:.Size,TEAM:.Labels.team,STATUS:.EOL.Status,ADDONS:.Addons")
+	r.NoError(err)
+	r.Len(columns, 7)
+
+	report := types.InventoryReport{Resources: []types.Versioned{
+		types.EKSCluster{VersionedResource: types.VersionedResource{Kind: types.KindEKSCluster, ID: "prod", EOL: types.EOLStatus{Status: types.StatusValid}},
+			PlatformVersion: "eks.5", Addons: []types.EKSClusterAddon{{Name: "vpc-cni", Version: "v1.12.0"}}},
+		types.ACMCertificate{VersionedResource: types.VersionedResource{Kind: types.KindACMCertificate, ID: "cert", Labels: map[string]string{"team": "web"}},
+			DomainName: "example.com"},
+		types.Volume{VersionedResource: types.VersionedResource{Kind: types.KindVolume, ID: "vol"}, Size: 100},
+	}}
+	r.Equal([][]string{
+		{"prod", "eks.5", NoneValue, NoneValue, NoneValue, "VALID", `[{"name":"vpc-cni","version":"v1.12.0"}]`},
+		{"cert", NoneValue, "example.com", NoneValue, "web", "", NoneValue},
+		{"vol", NoneValue, NoneValue, "100", NoneValue, "", NoneValue},
+	}, ColumnsToTable(report, columns))
+
+	_, err = ParseColumns("NAME")
+	r.Error(err)
+	_, err = ParseColumns("NAME:")
+	r.Error(err)
+}
+
+func TestReportToRecords(t *testing.T) {
+	r := require.New(t)
+
+	longID := "a-very-long-lambda-function-name-which-the-text-table-truncates"
+	report := types.InventoryReport{Resources: []types.Versioned{
+		types.Lambda{VersionedResource: types.VersionedResource{Kind: types.KindLambda, ID: longID, Arn: "arn:aws:lambda:us-west-2:123456789012:function:" + longID,
+			Parents: []types.ParentResource{{Kind: types.KindAWSAccount, ID: "123456789012"}}, Version: "python3.8",
+			EOL:             types.EOLStatus{EOLDate: "2024-10-14", RemainingDays: 0, Status: types.StatusCritical},
+			GitOpsReference: types.GitOpsReference{Repo: "org/infra", Branch: "main", Path: "lambdas", Workspace: "org/lambdas"},
+			Labels:          map[string]string{"team": "api"},
+			Remediation:     &types.Remediation{TargetVersion: "python3.12", Path: []string{"python3.12"}}},
+			Engine: "python3.8"},
+		types.Volume{VersionedResource: types.VersionedResource{Kind: types.KindVolume, ID: "vol-1"}, VolumeType: "gp2", Size: 100},
+	}}
+
+	header, records := ReportToRecords(report, "team")
+	r.Equal(append(append([]string{}, recordHeader...), "tag:team", "lambda.engine", "vol.volumetype", "vol.size"), header)
+	r.Equal([][]string{
+		{"lambda", longID, "aws:123456789012", "arn:aws:lambda:us-west-2:123456789012:function:" + longID, "python3.8", "", "CRITICAL", "2024-10-14", "", "0",
+			"", "org/infra", "main", "lambdas", "org/lambdas", "python3.12", "api", "python3.8", "", ""},
+		{"vol", "vol-1", "", "", "", "", "", "", "", "0", "", "", "", "", "", "", "", "", "gp2", "100"},
+	}, records)
+}
@@ -0,0 +1,37 @@
+package util
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+	"gopkg.in/yaml.v2"
+)
+
+// Config is the content of the camelot configuration file (--config)
+type Config struct {
+	Endpoints  Endpoints                              `yaml:"endpoints,omitempty"`
+	Proxy      string                                 `yaml:"proxy,omitempty"`
+	Policy     map[types.ResourceKind]ThresholdConfig `yaml:"policy,omitempty"`
+	HistoryDir string                                 `yaml:"history_dir,omitempty"`
+	TagColumns []string                               `yaml:"tag_columns,omitempty"`
+	Ownership  OwnershipConfig                        `yaml:"ownership,omitempty"`
+}
+
+type OwnershipConfig struct {
+	Tags []string `yaml:"tags,omitempty"` // AWS tags naming the owner, defaults to owner and team
+	File string   `yaml:"file,omitempty"` // fallback owner mapping
+}
+
+func LoadConfig(path string) (*Config, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read config file %s: %w", path, err)
+	}
+	config := &Config{}
+	err = yaml.UnmarshalStrict(b, config)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse config file %s: %w", path, err)
+	}
+	return config, nil
+}
@@ -0,0 +1,59 @@
+package util
+
+import (
+	"sort"
+
+	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+)
+
+// Correlate fills the gitops reference of the resources in report with the TFC workspace (and its repo,
+// branch and path) whose state holds their ARN, taken from the tfc resources of tfcReport. Both may be
+// the same, merged report. When every TFC workspace state could be read, resources with an ARN that no
+// workspace manages are flagged as unmanaged (click-ops). It returns the number of managed and unmanaged
+// resources.
+func Correlate(report, tfcReport *types.InventoryReport) (managed, unmanaged int) {
+	if report == nil || tfcReport == nil {
+		return 0, 0
+	}
+
+	references := map[string][]types.GitOpsReference{}
+	for _, item := range tfcReport.Resources {
+		resource := item.GetVersionedResource()
+		if resource.Kind == types.KindTFCResource && len(resource.Arn) > 0 {
+			references[resource.Arn] = append(references[resource.Arn], resource.GitOpsReference)
+		}
+	}
+	// A resource in the state of several workspaces is attributed to the first one, by name
+	for _, refs := range references {
+		sort.SliceStable(refs, func(i, j int) bool {
+			return refs[i].Workspace < refs[j].Workspace
+		})
+	}
+	complete := true
+	for _, scrapeError := range tfcReport.Errors {
+		if scrapeError.Source == "tfc" {
+			complete = false
+		}
+	}
+
+	report.UpdateResources(func(resource *types.VersionedResource) {
+		if len(resource.Arn) == 0 || isTFCKind(resource.Kind) {
+			return
+		}
+		if refs, ok := references[resource.Arn]; ok {
+			resource.GitOpsReference = refs[0]
+			resource.Unmanaged = false
+			managed++
+			return
+		}
+		if complete {
+			resource.Unmanaged = true
+			unmanaged++
+		}
+	})
+	return managed, unmanaged
+}
+
+func isTFCKind(kind types.ResourceKind) bool {
+	return kind == types.KindTFCResource || kind == types.KindTFCWorkspace || kind == types.KindTFCProvider
+}
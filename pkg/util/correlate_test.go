@@ -0,0 +1,59 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCorrelate(t *testing.T) {
+	r := require.New(t)
+
+	clusterArn := "arn:aws:eks:us-west-2:123456789012:cluster/prod"
+	dbArn := "arn:aws:rds:us-west-2:123456789012:cluster:analytics"
+	tfcReport := &types.InventoryReport{Resources: []types.Versioned{
+		types.TfcResource{VersionedResource: types.VersionedResource{Kind: types.KindTFCResource, ID: "eks:cluster/prod", Arn: clusterArn,
+			GitOpsReference: types.GitOpsReference{Repo: "https://github.com/org/infra", Branch: "main", Path: "envs/staging", Workspace: "org/staging"}}},
+		types.TfcResource{VersionedResource: types.VersionedResource{Kind: types.KindTFCResource, ID: "eks:cluster/prod", Arn: clusterArn,
+			GitOpsReference: types.GitOpsReference{Repo: "https://github.com/org/infra", Branch: "main", Path: "envs/prod", Workspace: "org/prod"}}},
+	}}
+	report := &types.InventoryReport{Resources: []types.Versioned{
+		types.EKSCluster{VersionedResource: types.VersionedResource{Kind: types.KindEKSCluster, ID: "prod", Arn: clusterArn}},
+		types.HelmRelease{VersionedResource: types.VersionedResource{Kind: types.KindHelmRelease, ID: "ingress"}},
+		types.RDSCluster{VersionedResource: types.VersionedResource{Kind: types.KindRDSCluster, ID: "analytics", Arn: dbArn}},
+	}}
+
+	managed, unmanaged := Correlate(report, tfcReport)
+	r.Equal(1, managed)
+	r.Equal(1, unmanaged)
+
+	cluster := report.Resources[0].GetVersionedResource()
+	r.Equal(types.GitOpsReference{Repo: "https://github.com/org/infra", Branch: "main", Path: "envs/prod", Workspace: "org/prod"}, cluster.GitOpsReference)
+	r.False(cluster.Unmanaged)
+	r.False(report.Resources[1].GetVersionedResource().Unmanaged)
+	r.True(report.Resources[2].GetVersionedResource().Unmanaged)
+
+	r.Equal([]string{"prod"}, filteredIDs(report, "managed=true"))
+	r.Equal([]string{"analytics"}, filteredIDs(report, "managed=false"))
+
+	// A workspace whose state could not be read may manage any resource
+	tfcReport.Errors = []types.ScrapeError{{Source: "tfc", Message: "unable to read state"}}
+	report.Resources[2] = types.RDSCluster{VersionedResource: types.VersionedResource{Kind: types.KindRDSCluster, ID: "analytics", Arn: dbArn}}
+	managed, unmanaged = Correlate(report, tfcReport)
+	r.Equal(1, managed)
+	r.Equal(0, unmanaged)
+	r.False(report.Resources[2].GetVersionedResource().Unmanaged)
+}
+
+func filteredIDs(report *types.InventoryReport, filter ...string) []string {
+	f, err := CreateFilter(filter)
+	if err != nil {
+		return []string{err.Error()}
+	}
+	ids := []string{}
+	for _, item := range FilterReport(report, f).Resources {
+		ids = append(ids, item.GetVersionedResource().ID)
+	}
+	return ids
+}
@@ -0,0 +1,217 @@
+package util
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+	"github.com/hashicorp/go-version"
+)
+
+const cycloneDXSchema = "http://cyclonedx.org/schema/bom-1.5.schema.json"
+
+// CycloneDXBom is a CycloneDX 1.5 BOM, as imported by Dependency-Track. Only the properties camelot fills
+// are modelled.
+type CycloneDXBom struct {
+	Schema      string               `json:"$schema"`
+	BomFormat   string               `json:"bomFormat"`
+	SpecVersion string               `json:"specVersion"`
+	Version     int                  `json:"version"`
+	Metadata    CycloneDXMetadata    `json:"metadata"`
+	Components  []CycloneDXComponent `json:"components"`
+}
+
+type CycloneDXMetadata struct {
+	Timestamp  string              `json:"timestamp"`
+	Tools      CycloneDXTools      `json:"tools"`
+	Properties []CycloneDXProperty `json:"properties,omitempty"`
+}
+
+type CycloneDXTools struct {
+	Components []CycloneDXComponent `json:"components"`
+}
+
+type CycloneDXComponent struct {
+	BomRef      string              `json:"bom-ref,omitempty"`
+	Type        string              `json:"type"`
+	Group       string              `json:"group,omitempty"`
+	Name        string              `json:"name"`
+	Version     string              `json:"version,omitempty"`
+	Description string              `json:"description,omitempty"`
+	Purl        string              `json:"purl,omitempty"`
+	Properties  []CycloneDXProperty `json:"properties,omitempty"`
+}
+
+type CycloneDXProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// cycloneDXTypes are the component types of the resource kinds, other kinds are applications
+var cycloneDXTypes = map[types.ResourceKind]string{
+	types.KindEKSCluster:      "platform",
+	types.KindLambda:          "platform",
+	types.KindMachineImage:    "operating-system",
+	types.KindTFCProvider:     "library",
+	types.KindTerrfaormModule: "library",
+	types.KindACMCertificate:  "data",
+	types.KindVolume:          "device",
+}
+
+// ReportToCycloneDX turns every resource of the report into a CycloneDX component named after what is
+// versioned: the chart of a Helm release, the engine of an RDS cluster or the runtime of a Lambda. Helm
+// charts and Terraform providers and modules have a purl. The resource itself, its status and its end of
+// life dates are component properties.
+func ReportToCycloneDX(report types.InventoryReport, now time.Time) CycloneDXBom {
+	components := []CycloneDXComponent{}
+	seen := map[string]bool{}
+	for _, item := range report.Resources {
+		component := cycloneDXComponent(report, item)
+		// The same resource reported twice (e.g. on several branches) is a single component
+		if seen[component.BomRef] {
+			continue
+		}
+		seen[component.BomRef] = true
+		components = append(components, component)
+	}
+
+	metadata := CycloneDXMetadata{
+		Timestamp: now.UTC().Format(time.RFC3339),
+		Tools:     CycloneDXTools{Components: []CycloneDXComponent{{Type: "application", Name: "camelot", Version: Version}}},
+	}
+	// A BOM of an incomplete report may be missing components
+	if len(report.Errors) > 0 {
+		metadata.Properties = append(metadata.Properties, CycloneDXProperty{Name: "camelot:complete", Value: "false"})
+		for _, e := range report.Errors {
+			metadata.Properties = append(metadata.Properties, CycloneDXProperty{Name: "camelot:error", Value: FormatScrapeError(e)})
+		}
+	}
+
+	return CycloneDXBom{
+		Schema:      cycloneDXSchema,
+		BomFormat:   "CycloneDX",
+		SpecVersion: "1.5",
+		Version:     1,
+		Metadata:    metadata,
+		Components:  components,
+	}
+}
+
+func cycloneDXComponent(report types.InventoryReport, item types.Versioned) CycloneDXComponent {
+	resource := item.GetVersionedResource()
+	component := CycloneDXComponent{
+		BomRef:      string(resource.Kind) + ":" + resource.ID,
+		Type:        "application",
+		Name:        resource.ID,
+		Version:     resource.Version,
+		Description: string(resource.Kind) + " " + resource.ID,
+	}
+	if len(resource.Parents) > 0 {
+		component.BomRef = string(resource.Kind) + ":" + FormatParents(resource.Parents) + "/" + resource.ID
+		component.Description += " in " + FormatParents(resource.Parents)
+	}
+	if typ, ok := cycloneDXTypes[resource.Kind]; ok {
+		component.Type = typ
+	}
+
+	switch r := item.(type) {
+	case types.EKSCluster:
+		component.Name = "kubernetes"
+	case types.RDSCluster:
+		if len(r.Engine) > 0 {
+			component.Name = r.Engine
+		}
+	case types.Lambda:
+		if len(r.Engine) > 0 {
+			component.Name = r.Engine
+		}
+	case types.HelmRelease:
+		if len(r.Chart) > 0 {
+			component.Name = r.Chart
+			component.Purl = purl("helm", []string{r.Chart}, resource.Version, "")
+		}
+	case types.TfcProvider:
+		// Providers are constrained rather than pinned, the purl only has a version when it is exact
+		segments := strings.Split(resource.ID, "/")
+		if len(segments) == 2 {
+			component.Group, component.Name = segments[0], segments[1]
+		}
+		purlVersion := ""
+		if _, err := version.NewVersion(resource.Version); err == nil {
+			purlVersion = resource.Version
+		}
+		component.Purl = purl("terraform", segments, purlVersion, "")
+	case types.TerraformModule:
+		source, subpath, _ := strings.Cut(resource.ID, "//")
+		component.Purl = purl("terraform", strings.Split(source, "/"), resource.Version, subpath)
+	}
+
+	component.Properties = cycloneDXProperties(report, resource)
+	return component
+}
+
+func cycloneDXProperties(report types.InventoryReport, resource types.VersionedResource) []CycloneDXProperty {
+	properties := []CycloneDXProperty{}
+	add := func(name, value string) {
+		if len(value) > 0 {
+			properties = append(properties, CycloneDXProperty{Name: "camelot:" + name, Value: value})
+		}
+	}
+	account := AccountOf(resource)
+	if len(account) == 0 {
+		account = report.Identity.AwsAccountNumber
+	}
+
+	add("kind", string(resource.Kind))
+	add("id", resource.ID)
+	add("arn", resource.Arn)
+	add("parent", FormatParents(resource.Parents))
+	add("account", account)
+	add("owner", resource.Owner)
+	add("current_version", resource.CurrentVersion)
+	add("status", string(resource.EOL.Status))
+	add("eol.date", resource.EOL.EOLDate)
+	add("eol.support_date", resource.EOL.SupportDate)
+	add("eol.extended_support_date", resource.EOL.ExtendedSupportDate)
+	if resource.EOL.LTS {
+		add("eol.lts", "true")
+	}
+	if len(resource.EOL.EOLDate) > 0 {
+		add("eol.remaining_days", strconv.Itoa(resource.EOL.RemainingDays))
+	}
+	if resource.Remediation != nil {
+		add("remediation.target_version", resource.Remediation.TargetVersion)
+	}
+	add("gitops.repo", resource.GitOpsReference.Repo)
+	add("gitops.file", resource.GitOpsReference.File)
+	add("gitops.workspace", resource.GitOpsReference.Workspace)
+	return properties
+}
+
+// purl renders a package URL, e.g. pkg:terraform/hashicorp/aws@5.40.0 or pkg:terraform/cztack@v0.60.0#aws-vpc
+func purl(typ string, segments []string, pkgVersion, subpath string) string {
+	escaper := strings.NewReplacer("@", "%40", "+", "%2B")
+	escape := func(s string) string {
+		return escaper.Replace(url.PathEscape(s))
+	}
+	escaped := []string{}
+	for _, segment := range segments {
+		if len(segment) > 0 {
+			escaped = append(escaped, escape(segment))
+		}
+	}
+	p := "pkg:" + typ + "/" + strings.Join(escaped, "/")
+	if len(pkgVersion) > 0 {
+		p += "@" + escape(pkgVersion)
+	}
+	if subpath = strings.Trim(subpath, "/"); len(subpath) > 0 {
+		escaped = []string{}
+		for _, segment := range strings.Split(subpath, "/") {
+			escaped = append(escaped, escape(segment))
+		}
+		p += "#" + strings.Join(escaped, "/")
+	}
+	return p
+}
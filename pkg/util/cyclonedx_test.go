@@ -0,0 +1,78 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportToCycloneDX(t *testing.T) {
+	r := require.New(t)
+	report := types.InventoryReport{Identity: types.Indentity{AwsAccountNumber: "123"}, Resources: []types.Versioned{
+		types.HelmRelease{VersionedResource: types.VersionedResource{Kind: types.KindHelmRelease, ID: "ingress/nginx", Version: "4.7.1",
+			Parents: []types.ParentResource{{Kind: types.KindEKSCluster, ID: "prod"}}, EOL: types.EOLStatus{Status: types.StatusWarning}}, Chart: "ingress-nginx"},
+		types.TfcProvider{VersionedResource: types.VersionedResource{Kind: types.KindTFCProvider, ID: "hashicorp/aws", Version: "~> 2.0", CurrentVersion: "5.40.0"}},
+		types.TfcProvider{VersionedResource: types.VersionedResource{Kind: types.KindTFCProvider, ID: "hashicorp/random", Version: "3.6.0"}},
+		types.TerraformModule{VersionedResource: types.VersionedResource{Kind: types.KindTerrfaormModule, ID: "cztack//aws-vpc", Version: "v0.60.0",
+			Parents: []types.ParentResource{{Kind: types.KindGithubRepo, ID: "infra"}}}},
+		types.RDSCluster{VersionedResource: types.VersionedResource{Kind: types.KindRDSCluster, ID: "analytics", Version: "13.7",
+			EOL: types.EOLStatus{EOLDate: "2025-11-13", SupportDate: "2024-11-14", RemainingDays: 300, Status: types.StatusValid}}, Engine: "aurora-postgresql"},
+		// Reported twice
+		types.RDSCluster{VersionedResource: types.VersionedResource{Kind: types.KindRDSCluster, ID: "analytics", Version: "13.7"}, Engine: "aurora-postgresql"},
+		types.Volume{VersionedResource: types.VersionedResource{Kind: types.KindVolume, ID: "vol-1"}},
+	}}
+
+	bom := ReportToCycloneDX(report, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	r.Equal("CycloneDX", bom.BomFormat)
+	r.Equal("1.5", bom.SpecVersion)
+	r.Equal("2024-01-01T00:00:00Z", bom.Metadata.Timestamp)
+	r.Len(bom.Components, 6)
+
+	helm := bom.Components[0]
+	r.Equal("ingress-nginx", helm.Name)
+	r.Equal("pkg:helm/ingress-nginx@4.7.1", helm.Purl)
+	r.Equal("helm:eks:prod/ingress/nginx", helm.BomRef)
+	r.Contains(helm.Properties, CycloneDXProperty{Name: "camelot:status", Value: "WARNING"})
+
+	r.Equal("pkg:terraform/hashicorp/aws", bom.Components[1].Purl)
+	r.Equal("hashicorp", bom.Components[1].Group)
+	r.Equal("aws", bom.Components[1].Name)
+	r.Equal("~> 2.0", bom.Components[1].Version)
+	r.Equal("pkg:terraform/hashicorp/random@3.6.0", bom.Components[2].Purl)
+	r.Equal("pkg:terraform/cztack@v0.60.0#aws-vpc", bom.Components[3].Purl)
+	r.Equal("library", bom.Components[3].Type)
+
+	rds := bom.Components[4]
+	r.Equal("aurora-postgresql", rds.Name)
+	r.Empty(rds.Purl)
+	for _, property := range []CycloneDXProperty{
+		{Name: "camelot:id", Value: "analytics"},
+		{Name: "camelot:account", Value: "123"},
+		{Name: "camelot:eol.date", Value: "2025-11-13"},
+		{Name: "camelot:eol.support_date", Value: "2024-11-14"},
+		{Name: "camelot:eol.remaining_days", Value: "300"},
+	} {
+		r.Contains(rds.Properties, property)
+	}
+
+	r.Equal("vol-1", bom.Components[5].Name)
+	r.Equal("device", bom.Components[5].Type)
+	r.Empty(bom.Metadata.Properties)
+
+	// The scrape errors of an incomplete report are properties of the BOM
+	report.Errors = []types.ScrapeError{{Source: "aws", Extractor: "rds", Account: "123", Region: "us-east-1",
+		Resource: &types.ParentResource{Kind: types.KindRDSCluster, ID: "analytics"}, Message: "throttled"}}
+	bom = ReportToCycloneDX(report, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	r.Equal([]CycloneDXProperty{
+		{Name: "camelot:complete", Value: "false"},
+		{Name: "camelot:error", Value: "aws/rds/123/us-east-1/rds:analytics: throttled"},
+	}, bom.Metadata.Properties)
+}
+
+func TestPurl(t *testing.T) {
+	r := require.New(t)
+	r.Equal("pkg:terraform/github.com/acme/modules@v1.0.0#aws/vpc", purl("terraform", []string{"github.com", "acme", "modules"}, "v1.0.0", "/aws/vpc/"))
+	r.Equal("pkg:helm/my%40chart@1.0.0%2Bbuild", purl("helm", []string{"my@chart"}, "1.0.0+build", ""))
+}
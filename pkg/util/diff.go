@@ -0,0 +1,255 @@
+package util
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+	"github.com/hashicorp/go-version"
+)
+
+type ChangeType string
+
+const (
+	ChangeAdded      ChangeType = "added"
+	ChangeRemoved    ChangeType = "removed"
+	ChangeUpgraded   ChangeType = "upgraded"
+	ChangeDowngraded ChangeType = "downgraded"
+	ChangeVersion    ChangeType = "version-changed" // the versions cannot be ordered
+	ChangeStatus     ChangeType = "status-changed"
+)
+
+const (
+	TrendBetter = "better"
+	TrendWorse  = "worse"
+)
+
+// ChangeTypes lists the change types in the order they are reported
+var ChangeTypes = []ChangeType{ChangeAdded, ChangeRemoved, ChangeUpgraded, ChangeDowngraded, ChangeVersion, ChangeStatus}
+
+type ResourceChange struct {
+	Change   ChangeType               `json:"change"`
+	Identity string                   `json:"identity"`
+	Trend    string                   `json:"trend,omitempty"` // better or worse, when the status changed
+	Before   *types.VersionedResource `json:"before,omitempty"`
+	After    *types.VersionedResource `json:"after,omitempty"`
+}
+
+// Resource returns the latest known state of the changed resource
+func (c ResourceChange) Resource() types.VersionedResource {
+	if c.After != nil {
+		return *c.After
+	}
+	return *c.Before
+}
+
+type ReportDiff struct {
+	Changes []ResourceChange `json:"changes"`
+}
+
+// ResourceIdentity is a stable key of a resource across scrapes: its kind, parents, id and arn
+func ResourceIdentity(item types.VersionedResource) string {
+	var sb strings.Builder
+	sb.WriteString(string(item.Kind))
+	for _, p := range item.Parents {
+		sb.WriteString("|")
+		sb.WriteString(string(p.Kind))
+		sb.WriteString(":")
+		sb.WriteString(p.ID)
+	}
+	sb.WriteString("|")
+	sb.WriteString(item.ID)
+	if len(item.Arn) > 0 {
+		sb.WriteString("|")
+		sb.WriteString(item.Arn)
+	}
+	return sb.String()
+}
+
+func DiffReports(before, after *types.InventoryReport) ReportDiff {
+	diff := ReportDiff{Changes: []ResourceChange{}}
+
+	index := func(report *types.InventoryReport) map[string][]types.VersionedResource {
+		m := map[string][]types.VersionedResource{}
+		if report == nil {
+			return m
+		}
+		for _, item := range report.Resources {
+			resource := item.GetVersionedResource()
+			identity := ResourceIdentity(resource)
+			m[identity] = append(m[identity], resource)
+		}
+		return m
+	}
+	beforeIndex := index(before)
+	afterIndex := index(after)
+
+	for identity, afterResources := range afterIndex {
+		beforeResources := beforeIndex[identity]
+		for i := range afterResources {
+			a := afterResources[i]
+			if i >= len(beforeResources) {
+				diff.Changes = append(diff.Changes, ResourceChange{Change: ChangeAdded, Identity: identity, After: &a})
+				continue
+			}
+			b := beforeResources[i]
+			if change, ok := compareResources(b, a); ok {
+				change.Identity = identity
+				diff.Changes = append(diff.Changes, change)
+			}
+		}
+	}
+	for identity, beforeResources := range beforeIndex {
+		for i := len(afterIndex[identity]); i < len(beforeResources); i++ {
+			b := beforeResources[i]
+			diff.Changes = append(diff.Changes, ResourceChange{Change: ChangeRemoved, Identity: identity, Before: &b})
+		}
+	}
+
+	order := map[ChangeType]int{}
+	for i, c := range ChangeTypes {
+		order[c] = i
+	}
+	sort.SliceStable(diff.Changes, func(i, j int) bool {
+		if diff.Changes[i].Change != diff.Changes[j].Change {
+			return order[diff.Changes[i].Change] < order[diff.Changes[j].Change]
+		}
+		return diff.Changes[i].Identity < diff.Changes[j].Identity
+	})
+	return diff
+}
+
+func compareResources(before, after types.VersionedResource) (ResourceChange, bool) {
+	change := ResourceChange{Before: &before, After: &after}
+	if before.EOL.Status != after.EOL.Status {
+		change.Change = ChangeStatus
+		change.Trend = TrendBetter
+		if statusSeverity(after.EOL.Status) > statusSeverity(before.EOL.Status) {
+			change.Trend = TrendWorse
+		}
+	}
+	if before.Version != after.Version {
+		switch CompareVersions(before.Version, after.Version) {
+		case -1:
+			change.Change = ChangeUpgraded
+		case 1:
+			change.Change = ChangeDowngraded
+		default:
+			change.Change = ChangeVersion
+		}
+	}
+	return change, len(change.Change) > 0
+}
+
+func statusSeverity(status types.Status) int {
+	switch status {
+	case types.StatusCritical:
+		return 2
+	case types.StatusWarning:
+		return 1
+	}
+	return 0
+}
+
+// ParseStatusThreshold parses a --fail-on value, warning or critical, into the least severe status it
+// fails on. An empty value never fails.
+func ParseStatusThreshold(value string) (types.Status, error) {
+	switch strings.ToLower(value) {
+	case "":
+		return "", nil
+	case "warning":
+		return types.StatusWarning, nil
+	case "critical":
+		return types.StatusCritical, nil
+	}
+	return "", fmt.Errorf("invalid status threshold %q, expected warning or critical", value)
+}
+
+// CountAtOrAbove counts the resources which are at least as severe as the threshold
+func CountAtOrAbove(report types.InventoryReport, threshold types.Status) int {
+	if len(threshold) == 0 {
+		return 0
+	}
+	count := 0
+	for _, item := range report.Resources {
+		if statusSeverity(item.GetVersionedResource().EOL.Status) >= statusSeverity(threshold) {
+			count++
+		}
+	}
+	return count
+}
+
+var versionSuffixRegexp = regexp.MustCompile(`^(.*?)v?(\d+(\.\d+)*)(\.x)?$`)
+
+// CompareVersions returns -1, 0 or 1 when v1 is older than, the same as, or newer than v2. Versions
+// with a common prefix, like lambda runtimes (python3.8, python3.11), are compared by their numbers.
+// Versions which cannot be compared are treated as equal.
+func CompareVersions(v1, v2 string) int {
+	cmp, _ := compareVersions(v1, v2)
+	return cmp
+}
+
+// compareVersions also tells whether the versions could be compared
+func compareVersions(v1, v2 string) (int, bool) {
+	if v1 == v2 {
+		return 0, true
+	}
+	prefix1, ver1, ok1 := parseVersion(v1)
+	prefix2, ver2, ok2 := parseVersion(v2)
+	if !ok1 || !ok2 || prefix1 != prefix2 {
+		return 0, false
+	}
+	return ver1.Compare(ver2), true
+}
+
+// parseVersion splits a version into its prefix and number, e.g. python and 3.8 for python3.8
+func parseVersion(v string) (string, *version.Version, bool) {
+	m := versionSuffixRegexp.FindStringSubmatch(v)
+	if m == nil {
+		return "", nil, false
+	}
+	ver, err := version.NewVersion(m[2])
+	if err != nil {
+		return "", nil, false
+	}
+	return m[1], ver, true
+}
+
+// Summary counts the changes of each type
+func (d ReportDiff) Summary() map[ChangeType]int {
+	summary := map[ChangeType]int{}
+	for _, c := range d.Changes {
+		summary[c.Change]++
+	}
+	return summary
+}
+
+func DiffToTable(diff ReportDiff) [][]string {
+	var table [][]string
+	for _, c := range diff.Changes {
+		item := c.Resource()
+		versions, statuses := item.Version, string(item.EOL.Status)
+		if c.Before != nil && c.After != nil {
+			versions = transition(c.Before.Version, c.After.Version)
+			statuses = transition(string(c.Before.EOL.Status), string(c.After.EOL.Status))
+		}
+		table = append(table, []string{
+			string(c.Change),
+			string(item.Kind),
+			truncate(item.ID, 40),
+			truncate(FormatParents(item.Parents), 80),
+			versions,
+			statuses,
+		})
+	}
+	return table
+}
+
+func transition(before, after string) string {
+	if before == after {
+		return after
+	}
+	return before + " -> " + after
+}
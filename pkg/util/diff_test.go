@@ -0,0 +1,92 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffReports(t *testing.T) {
+	r := require.New(t)
+
+	resource := func(kind types.ResourceKind, id, version string, status types.Status) types.VersionedResource {
+		return types.VersionedResource{
+			Kind:    kind,
+			ID:      id,
+			Version: version,
+			Parents: []types.ParentResource{{Kind: types.KindEKSCluster, ID: "cluster"}},
+			EOL:     types.EOLStatus{Status: status},
+		}
+	}
+
+	before := &types.InventoryReport{Resources: []types.Versioned{
+		types.EKSCluster{VersionedResource: resource(types.KindEKSCluster, "cluster", "1.27", types.StatusWarning)},
+		types.HelmRelease{VersionedResource: resource(types.KindHelmRelease, "removed", "1.0.0", types.StatusValid)},
+		types.HelmRelease{VersionedResource: resource(types.KindHelmRelease, "downgraded", "2.1.0", types.StatusValid)},
+		types.HelmRelease{VersionedResource: resource(types.KindHelmRelease, "aging", "1.0.0", types.StatusValid)},
+		types.HelmRelease{VersionedResource: resource(types.KindHelmRelease, "unchanged", "1.0.0", types.StatusValid)},
+		types.Lambda{VersionedResource: resource(types.KindLambda, "fn", "python3.8", types.StatusCritical)},
+	}}
+	after := &types.InventoryReport{Resources: []types.Versioned{
+		types.EKSCluster{VersionedResource: resource(types.KindEKSCluster, "cluster", "1.28", types.StatusValid)},
+		types.HelmRelease{VersionedResource: resource(types.KindHelmRelease, "added", "1.0.0", types.StatusValid)},
+		types.HelmRelease{VersionedResource: resource(types.KindHelmRelease, "downgraded", "2.0.9", types.StatusValid)},
+		types.HelmRelease{VersionedResource: resource(types.KindHelmRelease, "aging", "1.0.0", types.StatusCritical)},
+		types.HelmRelease{VersionedResource: resource(types.KindHelmRelease, "unchanged", "1.0.0", types.StatusValid)},
+		types.Lambda{VersionedResource: resource(types.KindLambda, "fn", "nodejs18.x", types.StatusValid)},
+	}}
+
+	diff := DiffReports(before, after)
+	changes := map[string]ResourceChange{}
+	for _, c := range diff.Changes {
+		changes[c.Resource().ID] = c
+	}
+	r.Len(changes, 6)
+	r.Equal(ChangeAdded, changes["added"].Change)
+	r.Nil(changes["added"].Before)
+	r.Equal(ChangeRemoved, changes["removed"].Change)
+	r.Nil(changes["removed"].After)
+	r.Equal(ChangeUpgraded, changes["cluster"].Change)
+	r.Equal(TrendBetter, changes["cluster"].Trend)
+	r.Equal(ChangeDowngraded, changes["downgraded"].Change)
+	r.Equal(ChangeStatus, changes["aging"].Change)
+	r.Equal(TrendWorse, changes["aging"].Trend)
+	r.Equal(ChangeVersion, changes["fn"].Change)
+
+	// Changes are ordered by their type
+	r.Equal(ChangeAdded, diff.Changes[0].Change)
+	r.Equal(ChangeStatus, diff.Changes[len(diff.Changes)-1].Change)
+	r.Equal(map[ChangeType]int{ChangeAdded: 1, ChangeRemoved: 1, ChangeUpgraded: 1, ChangeDowngraded: 1, ChangeVersion: 1, ChangeStatus: 1}, diff.Summary())
+
+	r.Empty(DiffReports(after, after).Changes)
+}
+
+func TestCountAtOrAbove(t *testing.T) {
+	r := require.New(t)
+	report := *expressionReport()
+
+	threshold, err := ParseStatusThreshold("warning")
+	r.NoError(err)
+	r.Equal(3, CountAtOrAbove(report, threshold))
+	threshold, err = ParseStatusThreshold("CRITICAL")
+	r.NoError(err)
+	r.Equal(2, CountAtOrAbove(report, threshold))
+	threshold, err = ParseStatusThreshold("")
+	r.NoError(err)
+	r.Equal(0, CountAtOrAbove(report, threshold))
+
+	_, err = ParseStatusThreshold("valid")
+	r.Error(err)
+}
+
+func TestCompareVersions(t *testing.T) {
+	r := require.New(t)
+	r.Equal(-1, CompareVersions("1.27", "1.28"))
+	r.Equal(1, CompareVersions("v2.10.0", "v2.9.1"))
+	r.Equal(-1, CompareVersions("python3.8", "python3.11"))
+	r.Equal(-1, CompareVersions("nodejs16.x", "nodejs18.x"))
+	r.Equal(0, CompareVersions("python3.8", "nodejs18.x"))
+	r.Equal(0, CompareVersions("1.0.0", "1.0.0"))
+	r.Equal(0, CompareVersions("latest", "stable"))
+}
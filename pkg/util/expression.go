@@ -0,0 +1,535 @@
+package util
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+)
+
+// Expression is a parsed filter expression, like
+// `kind in (eks,rds) and eol.remaining_days < 90 and not parent.id ~ "sandbox-*"`
+type Expression interface {
+	Match(item types.VersionedResource) bool
+}
+
+type fieldType int
+
+const (
+	stringField fieldType = iota
+	numberField
+	versionField
+	boolField
+)
+
+type filterField struct {
+	typ fieldType
+	// values of the field, resources match a comparison when any of them does (e.g. any parent)
+	values func(item types.VersionedResource) []string
+	// fold compares values case-insensitively
+	fold bool
+}
+
+func one(value string) []string { return []string{value} }
+
+var filterFields = map[string]filterField{
+	"kind":            {typ: stringField, values: func(item types.VersionedResource) []string { return one(string(item.Kind)) }},
+	"id":              {typ: stringField, values: func(item types.VersionedResource) []string { return one(item.ID) }},
+	"arn":             {typ: stringField, values: func(item types.VersionedResource) []string { return one(item.Arn) }},
+	"version":         {typ: versionField, values: func(item types.VersionedResource) []string { return one(item.Version) }},
+	"current_version": {typ: versionField, values: func(item types.VersionedResource) []string { return one(item.CurrentVersion) }},
+	"status":          {typ: stringField, fold: true, values: func(item types.VersionedResource) []string { return one(string(item.EOL.Status)) }},
+	"owner":           {typ: stringField, values: func(item types.VersionedResource) []string { return one(item.Owner) }},
+	"eol.date":        {typ: stringField, values: func(item types.VersionedResource) []string { return one(item.EOL.EOLDate) }},
+	"eol.remaining_days": {typ: numberField, values: func(item types.VersionedResource) []string {
+		return one(strconv.Itoa(item.EOL.RemainingDays))
+	}},
+	"parent.kind": {typ: stringField, values: func(item types.VersionedResource) []string {
+		values := []string{}
+		for _, p := range item.Parents {
+			values = append(values, string(p.Kind))
+		}
+		return values
+	}},
+	"parent.id": {typ: stringField, values: func(item types.VersionedResource) []string {
+		values := []string{}
+		for _, p := range item.Parents {
+			values = append(values, p.ID)
+		}
+		return values
+	}},
+	"gitops.repo":      {typ: stringField, values: func(item types.VersionedResource) []string { return one(item.GitOpsReference.Repo) }},
+	"gitops.workspace": {typ: stringField, values: func(item types.VersionedResource) []string { return one(item.GitOpsReference.Workspace) }},
+	"gitops.file":      {typ: stringField, values: func(item types.VersionedResource) []string { return one(item.GitOpsReference.File) }},
+	// Resources which could not be correlated with TFC are neither managed nor unmanaged
+	"managed": {typ: boolField, values: func(item types.VersionedResource) []string {
+		if len(item.GitOpsReference.Workspace) > 0 {
+			return one("true")
+		}
+		if item.Unmanaged {
+			return one("false")
+		}
+		return nil
+	}},
+}
+
+// lookupField returns the field of a name, tag.<key> reads an AWS tag
+func lookupField(name string) (filterField, bool) {
+	if key, ok := strings.CutPrefix(name, "tag."); ok && len(key) > 0 {
+		return filterField{typ: stringField, values: func(item types.VersionedResource) []string {
+			if value, ok := item.Labels[key]; ok {
+				return one(value)
+			}
+			return nil
+		}}, true
+	}
+	field, ok := filterFields[strings.ToLower(name)]
+	return field, ok
+}
+
+// FilterFields lists the fields filter expressions can compare
+func FilterFields() []string {
+	fields := []string{"tag.<key>"}
+	for name := range filterFields {
+		fields = append(fields, name)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+type andExpression []Expression
+
+func (e andExpression) Match(item types.VersionedResource) bool {
+	for _, expr := range e {
+		if !expr.Match(item) {
+			return false
+		}
+	}
+	return true
+}
+
+type orExpression []Expression
+
+func (e orExpression) Match(item types.VersionedResource) bool {
+	for _, expr := range e {
+		if expr.Match(item) {
+			return true
+		}
+	}
+	return false
+}
+
+type notExpression struct {
+	Expression
+}
+
+func (e notExpression) Match(item types.VersionedResource) bool {
+	return !e.Expression.Match(item)
+}
+
+type comparison struct {
+	field   filterField
+	op      string
+	values  []string
+	pattern *regexp.Regexp
+}
+
+func (c comparison) Match(item types.VersionedResource) bool {
+	if c.op == "!=" {
+		return !comparison{field: c.field, op: "=", values: c.values}.Match(item)
+	}
+	for _, actual := range c.field.values(item) {
+		for _, value := range c.values {
+			if c.compare(actual, value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (c comparison) compare(actual, value string) bool {
+	switch c.op {
+	case "~", "=~":
+		return c.pattern.MatchString(actual)
+	}
+
+	var cmp int
+	switch c.field.typ {
+	case numberField:
+		a, err := strconv.Atoi(actual)
+		if err != nil {
+			return false
+		}
+		v, _ := strconv.Atoi(value)
+		cmp = a - v
+	case versionField:
+		if actual == value {
+			break
+		}
+		var ok bool
+		cmp, ok = compareVersions(actual, value)
+		if !ok {
+			return false
+		}
+	case boolField:
+		a, _ := strconv.ParseBool(actual)
+		v, _ := strconv.ParseBool(value)
+		if a != v {
+			cmp = 1
+		}
+	default:
+		if c.field.fold {
+			actual, value = strings.ToUpper(actual), strings.ToUpper(value)
+		}
+		cmp = strings.Compare(actual, value)
+	}
+
+	switch c.op {
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	}
+	return cmp == 0
+}
+
+type tokenType int
+
+const (
+	tokenEOF tokenType = iota
+	tokenWord
+	tokenString
+	tokenOperator
+	tokenLParen
+	tokenRParen
+	tokenComma
+)
+
+type token struct {
+	typ   tokenType
+	text  string
+	pos   int
+	value string // unquoted strings
+}
+
+func (t token) String() string {
+	if t.typ == tokenEOF {
+		return "end of expression"
+	}
+	return fmt.Sprintf("%q at position %d", t.text, t.pos+1)
+}
+
+func (t token) keyword(keyword string) bool {
+	return t.typ == tokenWord && strings.EqualFold(t.text, keyword)
+}
+
+var operators = []string{"==", "!=", "<=", ">=", "=~", "=", "<", ">", "~"}
+
+func tokenize(input string) ([]token, error) {
+	tokens := []token{}
+	for i := 0; i < len(input); {
+		c := rune(input[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '(':
+			tokens = append(tokens, token{typ: tokenLParen, text: "(", pos: i})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{typ: tokenRParen, text: ")", pos: i})
+			i++
+		case c == ',':
+			tokens = append(tokens, token{typ: tokenComma, text: ",", pos: i})
+			i++
+		case c == '"':
+			var sb strings.Builder
+			j := i + 1
+			for ; j < len(input) && input[j] != '"'; j++ {
+				if input[j] == '\\' && j+1 < len(input) {
+					j++
+				}
+				sb.WriteByte(input[j])
+			}
+			if j >= len(input) {
+				return nil, fmt.Errorf("unterminated string at position %d", i+1)
+			}
+			tokens = append(tokens, token{typ: tokenString, text: input[i : j+1], pos: i, value: sb.String()})
+			i = j + 1
+		case strings.ContainsRune("=!<>~", c):
+			op := ""
+			for _, o := range operators {
+				if strings.HasPrefix(input[i:], o) {
+					op = o
+					break
+				}
+			}
+			if len(op) == 0 {
+				return nil, fmt.Errorf("unknown operator at position %d", i+1)
+			}
+			tokens = append(tokens, token{typ: tokenOperator, text: op, pos: i})
+			i += len(op)
+		default:
+			j := i
+			for j < len(input) && !unicode.IsSpace(rune(input[j])) && !strings.ContainsRune(`(),"=!<>~`, rune(input[j])) {
+				j++
+			}
+			tokens = append(tokens, token{typ: tokenWord, text: input[i:j], pos: i, value: input[i:j]})
+			i = j
+		}
+	}
+	return append(tokens, token{typ: tokenEOF, pos: len(input)}), nil
+}
+
+type expressionParser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *expressionParser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *expressionParser) next() token {
+	t := p.tokens[p.pos]
+	if t.typ != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+// ParseFilterExpression parses a filter expression. Comparisons (`field op value`) are combined with
+// `and`, `or`, `not` and parentheses; the operators are =, !=, <, <=, >, >= (numeric for
+// eol.remaining_days, semver-aware for versions), ~ (glob), =~ (regexp) and `in (a,b)`.
+func ParseFilterExpression(input string) (Expression, error) {
+	tokens, err := tokenize(input)
+	if err != nil {
+		return nil, err
+	}
+	p := &expressionParser{tokens: tokens}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.typ != tokenEOF {
+		return nil, fmt.Errorf("unexpected %s", t)
+	}
+	return expr, nil
+}
+
+func (p *expressionParser) parseOr() (Expression, error) {
+	expr, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	or := orExpression{expr}
+	for p.peek().keyword("or") {
+		p.next()
+		expr, err = p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		or = append(or, expr)
+	}
+	if len(or) == 1 {
+		return or[0], nil
+	}
+	return or, nil
+}
+
+func (p *expressionParser) parseAnd() (Expression, error) {
+	expr, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	and := andExpression{expr}
+	for p.peek().keyword("and") {
+		p.next()
+		expr, err = p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		and = append(and, expr)
+	}
+	if len(and) == 1 {
+		return and[0], nil
+	}
+	return and, nil
+}
+
+func (p *expressionParser) parseNot() (Expression, error) {
+	if p.peek().keyword("not") {
+		p.next()
+		expr, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return notExpression{expr}, nil
+	}
+	if p.peek().typ == tokenLParen {
+		p.next()
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if t := p.next(); t.typ != tokenRParen {
+			return nil, fmt.Errorf("expected ) instead of %s", t)
+		}
+		return expr, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *expressionParser) parseComparison() (Expression, error) {
+	name := p.next()
+	if name.typ != tokenWord {
+		return nil, fmt.Errorf("expected a field instead of %s", name)
+	}
+	field, ok := lookupField(name.text)
+	if !ok {
+		return nil, fmt.Errorf("unknown field %q, supported fields are: %s", name.text, strings.Join(FilterFields(), ", "))
+	}
+
+	c := comparison{field: field}
+	op := p.next()
+	switch {
+	case op.keyword("in"):
+		c.op = "="
+		if t := p.next(); t.typ != tokenLParen {
+			return nil, fmt.Errorf("expected ( instead of %s", t)
+		}
+		values, err := p.parseValues()
+		if err != nil {
+			return nil, err
+		}
+		if t := p.next(); t.typ != tokenRParen {
+			return nil, fmt.Errorf("expected ) instead of %s", t)
+		}
+		c.values = values
+	case op.typ == tokenOperator:
+		c.op = op.text
+		if c.op == "==" {
+			c.op = "="
+		}
+		// A value list (status=warning,critical) matches any of its values, a missing value is empty
+		values, err := p.parseValues()
+		if err != nil {
+			return nil, err
+		}
+		c.values = values
+		if len(values) > 1 && c.op != "=" && c.op != "!=" {
+			return nil, fmt.Errorf("%s compares a single value", c.op)
+		}
+	default:
+		return nil, fmt.Errorf("expected an operator after %s instead of %s", name.text, op)
+	}
+
+	if err := c.validate(name.text); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (p *expressionParser) parseValues() ([]string, error) {
+	values := []string{}
+	for {
+		t := p.peek()
+		switch {
+		case t.typ == tokenString || (t.typ == tokenWord && !t.keyword("and") && !t.keyword("or")):
+			p.next()
+			values = append(values, t.value)
+		case t.typ == tokenComma:
+			values = append(values, "")
+		default:
+			if len(values) == 0 {
+				values = append(values, "")
+			}
+			return values, nil
+		}
+		if p.peek().typ != tokenComma {
+			return values, nil
+		}
+		p.next()
+	}
+}
+
+func (c *comparison) validate(name string) error {
+	switch c.op {
+	case "<", "<=", ">", ">=":
+		if c.field.typ != numberField && c.field.typ != versionField {
+			return fmt.Errorf("%s cannot be compared with %s", name, c.op)
+		}
+	case "~":
+		pattern, err := globRegexp(c.values[0])
+		if err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", c.values[0], err)
+		}
+		c.pattern = pattern
+		return nil
+	case "=~":
+		pattern, err := regexp.Compile(c.values[0])
+		if err != nil {
+			return fmt.Errorf("invalid regular expression %q: %w", c.values[0], err)
+		}
+		c.pattern = pattern
+		return nil
+	}
+
+	for _, value := range c.values {
+		switch c.field.typ {
+		case numberField:
+			if _, err := strconv.Atoi(value); err != nil {
+				return fmt.Errorf("%s compares numbers, not %q", name, value)
+			}
+		case boolField:
+			if _, err := strconv.ParseBool(value); err != nil {
+				return fmt.Errorf("%s is true or false, not %q", name, value)
+			}
+		}
+	}
+	return nil
+}
+
+// globRegexp compiles a glob into a regular expression matching whole values. Unlike path.Match, * matches
+// any run of characters, / included, as ids like stacks/prod or org/infra are not file paths.
+func globRegexp(glob string) (*regexp.Regexp, error) {
+	var sb strings.Builder
+	sb.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			sb.WriteString(".*")
+		case '?':
+			sb.WriteString(".")
+		case '\\':
+			if i+1 == len(glob) {
+				return nil, fmt.Errorf("trailing backslash")
+			}
+			i++
+			sb.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		case '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated character class")
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			sb.WriteString("[" + class + "]")
+			i += end + 1
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	sb.WriteString("$")
+	return regexp.Compile(sb.String())
+}
@@ -0,0 +1,109 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+	"github.com/stretchr/testify/require"
+)
+
+func expressionReport() *types.InventoryReport {
+	account := func(id string) []types.ParentResource {
+		return []types.ParentResource{{Kind: types.KindAWSAccount, ID: id}}
+	}
+	return &types.InventoryReport{Resources: []types.Versioned{
+		types.EKSCluster{VersionedResource: types.VersionedResource{Kind: types.KindEKSCluster, ID: "prod", Parents: account("prod-account"), Version: "1.24",
+			EOL: types.EOLStatus{RemainingDays: 20, Status: types.StatusCritical}, Labels: map[string]string{"team": "platform"}}},
+		types.EKSCluster{VersionedResource: types.VersionedResource{Kind: types.KindEKSCluster, ID: "sandbox-1", Parents: account("sandbox-account"), Version: "1.9",
+			EOL: types.EOLStatus{RemainingDays: 10, Status: types.StatusCritical}}},
+		types.RDSCluster{VersionedResource: types.VersionedResource{Kind: types.KindRDSCluster, ID: "analytics", Parents: account("prod-account"), Version: "13.7",
+			EOL: types.EOLStatus{RemainingDays: 200, Status: types.StatusValid}}},
+		types.Lambda{VersionedResource: types.VersionedResource{Kind: types.KindLambda, ID: "api", Parents: account("prod-account"), Version: "python3.8",
+			EOL: types.EOLStatus{RemainingDays: 60, Status: types.StatusWarning}}},
+	}}
+}
+
+func TestFilterExpressions(t *testing.T) {
+	r := require.New(t)
+	report := expressionReport()
+
+	tests := []struct {
+		filters  []string
+		expected []string
+	}{
+		{[]string{`kind in (eks,rds) and eol.remaining_days < 90 and not parent.id ~ "sandbox-*"`}, []string{"prod"}},
+		{[]string{"kind=eks or kind = rds"}, []string{"prod", "sandbox-1", "analytics"}},
+		{[]string{"version < 1.24"}, []string{"sandbox-1"}},
+		{[]string{"version >= 1.24"}, []string{"prod", "analytics"}},
+		{[]string{"version < python3.11"}, []string{"api"}},
+		{[]string{"version > 13 and version < 14"}, []string{"analytics"}},
+		{[]string{`id =~ "^(prod|api)$"`}, []string{"prod", "api"}},
+		{[]string{"status=warning,critical", "eol.remaining_days >= 20"}, []string{"prod", "api"}},
+		{[]string{"not (status = critical or kind = lambda)"}, []string{"analytics"}},
+		{[]string{"tag.team = platform"}, []string{"prod"}},
+		{[]string{"tag.team != platform"}, []string{"sandbox-1", "analytics", "api"}},
+		{[]string{"kind=eks", "kind=lambda"}, []string{"prod", "sandbox-1", "api"}},
+		{[]string{"kind=eks", "id != prod"}, []string{"sandbox-1"}},
+		{[]string{`owner = ""`, "KIND == rds"}, []string{"analytics"}},
+	}
+	for _, test := range tests {
+		filter, err := CreateFilter(test.filters)
+		r.NoError(err, test.filters)
+		ids := []string{}
+		for _, item := range FilterReport(report, filter).Resources {
+			ids = append(ids, item.GetVersionedResource().ID)
+		}
+		r.Equal(test.expected, ids, test.filters)
+	}
+}
+
+func TestInvalidFilterExpressions(t *testing.T) {
+	r := require.New(t)
+
+	for _, expr := range []string{
+		"kind",
+		"foo=bar",
+		"tag.=x",
+		"managed=maybe",
+		"kind = eks and",
+		"(kind = eks",
+		"kind = eks)",
+		"kind in eks",
+		"eol.remaining_days < soon",
+		"id < abc",
+		`id ~ "[a"`,
+		`id ~ "prod\\"`,
+		`id =~ "(a"`,
+		`id = "open`,
+		"kind => eks",
+	} {
+		_, err := CreateFilter([]string{expr})
+		r.Error(err, expr)
+	}
+
+	_, err := CreateFilter([]string{"foo=bar"})
+	r.ErrorContains(err, `unknown field "foo"`)
+	_, err = CreateFilter([]string{"kind = eks and"})
+	r.ErrorContains(err, "expected a field instead of end of expression")
+}
+
+func TestGlobFilter(t *testing.T) {
+	r := require.New(t)
+
+	module := types.VersionedResource{Kind: types.KindTerrfaormModule, ID: "stacks/prod/vpc", Parents: []types.ParentResource{{Kind: types.KindGithubRepo, ID: "org/infra"}}}
+	for expr, expected := range map[string]bool{
+		`id ~ "stacks*"`:        true,
+		`id ~ "*/vpc"`:          true,
+		`id ~ "stacks/?rod/*"`:  true,
+		`id ~ "stacks/[!s]*"`:   true,
+		`id ~ "stacks/[s]*"`:    false,
+		`id ~ "stacks"`:         false,
+		`id ~ "stacks.prod*"`:   false,
+		`parent.id ~ "org/*"`:   true,
+		`id ~ "stacks\\/prod*"`: true,
+	} {
+		filter, err := CreateFilter([]string{expr})
+		r.NoError(err, expr)
+		r.Equal(expected, filter.Expressions[0].Match(module), expr)
+	}
+}
@@ -0,0 +1,255 @@
+package util
+
+import (
+	"fmt"
+
+	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+)
+
+// GraphNode is a resource of the report, or a parent (like an AWS account) which only appears as such
+type GraphNode struct {
+	ID      string             `json:"id"`
+	Kind    types.ResourceKind `json:"kind,omitempty"`
+	Name    string             `json:"name"`
+	Version string             `json:"version,omitempty"`
+	Status  types.Status       `json:"status,omitempty"`
+	// Collapsed is the number of VALID resources a collapsed node stands for
+	Collapsed int `json:"collapsed,omitempty"`
+}
+
+// GraphEdge links a parent to its child
+type GraphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type Graph struct {
+	Nodes []GraphNode `json:"nodes"`
+	Edges []GraphEdge `json:"edges"`
+	// Errors are the scrape errors of the report, the graph is incomplete when there are any
+	Errors []types.ScrapeError `json:"errors,omitempty"`
+}
+
+// ReportGraph builds the graph of the parent chains of the resources: account -> EKS cluster -> Helm
+// release, org -> repo -> module, workspace -> resource. With collapseValid, subtrees in which every
+// resource is VALID are replaced by a single node per parent counting them.
+func ReportGraph(report types.InventoryReport, collapseValid bool) Graph {
+	graph := Graph{Nodes: []GraphNode{}, Edges: []GraphEdge{}}
+	index := map[string]int{}
+	refs := map[string][]string{}
+
+	addNode := func(node GraphNode) {
+		if i, ok := index[node.ID]; ok {
+			// The same resource seen twice, e.g. a TFC resource tracked on several branches
+			if len(graph.Nodes[i].Status) == 0 || statusSeverity(node.Status) > statusSeverity(graph.Nodes[i].Status) {
+				graph.Nodes[i].Status = node.Status
+			}
+			return
+		}
+		index[node.ID] = len(graph.Nodes)
+		graph.Nodes = append(graph.Nodes, node)
+	}
+
+	resources := []types.VersionedResource{}
+	chains := map[string]string{}
+	for _, item := range report.Resources {
+		resource := item.GetVersionedResource()
+		resources = append(resources, resource)
+		id := ResourceIdentity(resource)
+		addNode(GraphNode{ID: id, Kind: resource.Kind, Name: resource.ID, Version: resource.Version, Status: resource.EOL.Status})
+		chains[parentChain(resource.Kind, resource.ID, resource.Parents)] = id
+
+		// Parents refer to resources by kind and id, some (like workspaces) qualified by their own parent
+		ref := parentRef(resource.Kind, resource.ID)
+		refs[ref] = appendUnique(refs[ref], id)
+		if len(resource.Parents) > 0 {
+			qualified := parentRef(resource.Kind, resource.Parents[len(resource.Parents)-1].ID+"/"+resource.ID)
+			refs[qualified] = appendUnique(refs[qualified], id)
+		}
+	}
+
+	// The parents before a parent are its own chain, like the account of the cluster of a Helm release,
+	// which tells same-named resources apart. Parents without a chain are resolved by kind and id as long
+	// as that is not ambiguous, the others are nodes of their own.
+	resolve := func(resource types.VersionedResource, i int) string {
+		parent := resource.Parents[i]
+		if id, ok := chains[parentChain(parent.Kind, parent.ID, resource.Parents[:i])]; ok {
+			return id
+		}
+		ref := parentRef(parent.Kind, parent.ID)
+		if ids := refs[ref]; len(ids) == 1 {
+			return ids[0]
+		}
+		addNode(GraphNode{ID: ref, Kind: parent.Kind, Name: parent.ID})
+		return ref
+	}
+
+	parents := map[string][]string{}
+	for _, resource := range resources {
+		id := ResourceIdentity(resource)
+		for i := range resource.Parents {
+			parents[id] = appendUnique(parents[id], resolve(resource, i))
+		}
+	}
+
+	seen := map[GraphEdge]bool{}
+	for _, resource := range resources {
+		id := ResourceIdentity(resource)
+		for _, parent := range parents[id] {
+			// Link only the closest parents: a cluster's account is not linked to its helm releases
+			if isGrandparent(parent, parents[id], parents) {
+				continue
+			}
+			edge := GraphEdge{From: parent, To: id}
+			if !seen[edge] {
+				seen[edge] = true
+				graph.Edges = append(graph.Edges, edge)
+			}
+		}
+	}
+
+	if collapseValid {
+		graph = collapse(graph)
+	}
+	graph.Errors = report.Errors
+	return graph
+}
+
+func parentRef(kind types.ResourceKind, id string) string {
+	return string(kind) + ":" + id
+}
+
+// parentChain is the identity of a resource without its ARN, which its children cannot know
+func parentChain(kind types.ResourceKind, id string, parents []types.ParentResource) string {
+	return ResourceIdentity(types.VersionedResource{Kind: kind, ID: id, Parents: parents})
+}
+
+func isGrandparent(candidate string, siblings []string, parents map[string][]string) bool {
+	for _, sibling := range siblings {
+		if sibling == candidate {
+			continue
+		}
+		for _, p := range parents[sibling] {
+			if p == candidate {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
+
+// collapse replaces the all-VALID subtrees below each node with a single node counting their resources
+func collapse(graph Graph) Graph {
+	children := map[string][]string{}
+	hasParent := map[string]bool{}
+	for _, edge := range graph.Edges {
+		children[edge.From] = append(children[edge.From], edge.To)
+		hasParent[edge.To] = true
+	}
+	status := map[string]types.Status{}
+	for _, node := range graph.Nodes {
+		status[node.ID] = node.Status
+	}
+
+	valid := map[string]bool{}
+	var isValid func(id string) bool
+	isValid = func(id string) bool {
+		if v, ok := valid[id]; ok {
+			return v
+		}
+		valid[id] = true // breaks cycles
+		v := status[id] == types.StatusValid || (len(status[id]) == 0 && len(children[id]) > 0)
+		for _, child := range children[id] {
+			v = isValid(child) && v
+		}
+		valid[id] = v
+		return v
+	}
+
+	// Each resource of the all-VALID subtrees is counted once, by the summary of the first node reaching
+	// it. summarize returns the resources it counts and the summaries which counted the others.
+	owners := map[string]string{}
+	summarize := func(summaryID string, roots []string) (int, []string) {
+		count := 0
+		others := []string{}
+		visited := map[string]bool{}
+		var walk func(id string)
+		walk = func(id string) {
+			if visited[id] {
+				return
+			}
+			visited[id] = true
+			// Nodes without a status are not resources
+			if len(status[id]) > 0 {
+				if owner, ok := owners[id]; ok {
+					others = appendUnique(others, owner)
+				} else {
+					owners[id] = summaryID
+					count++
+				}
+			}
+			for _, child := range children[id] {
+				walk(child)
+			}
+		}
+		for _, root := range roots {
+			walk(root)
+		}
+		return count, others
+	}
+
+	collapsed := Graph{Nodes: []GraphNode{}, Edges: []GraphEdge{}}
+	validRoots := []string{}
+	for _, node := range graph.Nodes {
+		if isValid(node.ID) {
+			if !hasParent[node.ID] {
+				validRoots = append(validRoots, node.ID)
+			}
+			continue
+		}
+		collapsed.Nodes = append(collapsed.Nodes, node)
+
+		validChildren := []string{}
+		for _, child := range children[node.ID] {
+			if isValid(child) {
+				validChildren = append(validChildren, child)
+				continue
+			}
+			collapsed.Edges = append(collapsed.Edges, GraphEdge{From: node.ID, To: child})
+		}
+		if len(validChildren) == 0 {
+			continue
+		}
+		count, others := summarize(node.ID+"#valid", validChildren)
+		if count > 0 {
+			summary := collapsedNode(node.ID+"#valid", count)
+			collapsed.Nodes = append(collapsed.Nodes, summary)
+			collapsed.Edges = append(collapsed.Edges, GraphEdge{From: node.ID, To: summary.ID})
+		}
+		// Resources with several parents stay linked to each of them, through the summary counting them
+		for _, other := range others {
+			collapsed.Edges = append(collapsed.Edges, GraphEdge{From: node.ID, To: other})
+		}
+	}
+	if count, _ := summarize("#valid", validRoots); count > 0 {
+		collapsed.Nodes = append(collapsed.Nodes, collapsedNode("#valid", count))
+	}
+	return collapsed
+}
+
+func collapsedNode(id string, count int) GraphNode {
+	name := fmt.Sprintf("%d VALID resources", count)
+	if count == 1 {
+		name = "1 VALID resource"
+	}
+	return GraphNode{ID: id, Name: name, Status: types.StatusValid, Collapsed: count}
+}
@@ -0,0 +1,105 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+	"github.com/stretchr/testify/require"
+)
+
+func graphReport() types.InventoryReport {
+	account := []types.ParentResource{{Kind: types.KindAWSAccount, ID: "123456789012"}}
+	eol := func(status types.Status) types.EOLStatus { return types.EOLStatus{Status: status} }
+	return types.InventoryReport{Resources: []types.Versioned{
+		types.EKSCluster{VersionedResource: types.VersionedResource{Kind: types.KindEKSCluster, ID: "prod", Parents: account, Version: "1.24", EOL: eol(types.StatusCritical)}},
+		types.HelmRelease{VersionedResource: types.VersionedResource{Kind: types.KindHelmRelease, ID: "ingress", Parents: []types.ParentResource{{Kind: types.KindEKSCluster, ID: "prod"}}, EOL: eol(types.StatusWarning)}},
+		types.HelmRelease{VersionedResource: types.VersionedResource{Kind: types.KindHelmRelease, ID: "dns", Parents: []types.ParentResource{{Kind: types.KindEKSCluster, ID: "prod"}}, EOL: eol(types.StatusValid)}},
+		types.EKSCluster{VersionedResource: types.VersionedResource{Kind: types.KindEKSCluster, ID: "dev", Parents: account, EOL: eol(types.StatusValid)}},
+		types.HelmRelease{VersionedResource: types.VersionedResource{Kind: types.KindHelmRelease, ID: "ingress", Parents: []types.ParentResource{{Kind: types.KindEKSCluster, ID: "dev"}}, EOL: eol(types.StatusValid)}},
+		types.TfcWorkspace{VersionedResource: types.VersionedResource{Kind: types.KindTFCWorkspace, ID: "infra", Parents: []types.ParentResource{{Kind: types.KindTFCOrg, ID: "org"}}, EOL: eol(types.StatusValid)}},
+		types.TfcResource{VersionedResource: types.VersionedResource{Kind: types.KindTFCResource, ID: "eks:cluster/prod", Parents: []types.ParentResource{{Kind: types.KindTFCWorkspace, ID: "org/infra"}, account[0]}, EOL: eol(types.StatusValid)}},
+	}}
+}
+
+func edgeNames(graph Graph) []string {
+	names := map[string]string{}
+	for _, node := range graph.Nodes {
+		names[node.ID] = string(node.Kind) + ":" + node.Name
+	}
+	edges := []string{}
+	for _, edge := range graph.Edges {
+		edges = append(edges, names[edge.From]+" -> "+names[edge.To])
+	}
+	return edges
+}
+
+func TestReportGraph(t *testing.T) {
+	r := require.New(t)
+
+	graph := ReportGraph(graphReport(), false)
+	r.Len(graph.Nodes, 9) // 7 resources, the account and the org
+	r.Equal([]string{
+		"aws:123456789012 -> eks:prod",
+		"eks:prod -> helm:ingress",
+		"eks:prod -> helm:dns",
+		"aws:123456789012 -> eks:dev",
+		"eks:dev -> helm:ingress",
+		"tfc-org:org -> tfc-workspace:infra",
+		"tfc-workspace:infra -> tfc-resource:eks:cluster/prod",
+		"aws:123456789012 -> tfc-resource:eks:cluster/prod",
+	}, edgeNames(graph))
+
+	collapsed := ReportGraph(graphReport(), true)
+	r.Equal([]string{
+		"eks:prod -> helm:ingress",
+		"eks:prod -> :1 VALID resource",
+		"aws:123456789012 -> eks:prod",
+		"aws:123456789012 -> :3 VALID resources",
+	}, edgeNames(collapsed))
+	last := collapsed.Nodes[len(collapsed.Nodes)-1]
+	r.Equal("#valid", last.ID)
+	r.Equal(1, last.Collapsed) // the workspace of the org, its TFC resource is counted under the account
+	r.Empty(collapsed.Errors)
+
+	// The scrape errors are kept, collapsed or not
+	report := graphReport()
+	report.Errors = []types.ScrapeError{{Source: "aws", Extractor: "eks", Message: "access denied"}}
+	r.Equal(report.Errors, ReportGraph(report, false).Errors)
+	r.Equal(report.Errors, ReportGraph(report, true).Errors)
+}
+
+func TestReportGraphAccounts(t *testing.T) {
+	r := require.New(t)
+
+	eol := func(status types.Status) types.EOLStatus { return types.EOLStatus{Status: status} }
+	account := func(id string) types.ParentResource { return types.ParentResource{Kind: types.KindAWSAccount, ID: id} }
+	cluster := types.ParentResource{Kind: types.KindEKSCluster, ID: "prod"}
+	workspace := types.ParentResource{Kind: types.KindTFCWorkspace, ID: "org/infra"}
+	report := types.InventoryReport{Resources: []types.Versioned{
+		types.EKSCluster{VersionedResource: types.VersionedResource{Kind: types.KindEKSCluster, ID: "prod", Parents: []types.ParentResource{account("111")}, EOL: eol(types.StatusCritical)}},
+		types.HelmRelease{VersionedResource: types.VersionedResource{Kind: types.KindHelmRelease, ID: "ingress", Parents: []types.ParentResource{account("111"), cluster}, EOL: eol(types.StatusValid)}},
+		types.EKSCluster{VersionedResource: types.VersionedResource{Kind: types.KindEKSCluster, ID: "prod", Parents: []types.ParentResource{account("222")}, EOL: eol(types.StatusValid)}},
+		types.HelmRelease{VersionedResource: types.VersionedResource{Kind: types.KindHelmRelease, ID: "ingress", Parents: []types.ParentResource{account("222"), cluster}, EOL: eol(types.StatusValid)}},
+		types.TfcWorkspace{VersionedResource: types.VersionedResource{Kind: types.KindTFCWorkspace, ID: "infra", Parents: []types.ParentResource{{Kind: types.KindTFCOrg, ID: "org"}}, EOL: eol(types.StatusWarning)}},
+		types.TfcResource{VersionedResource: types.VersionedResource{Kind: types.KindTFCResource, ID: "eks:cluster/prod", Parents: []types.ParentResource{workspace, account("111")}, EOL: eol(types.StatusValid)}},
+	}}
+
+	// Same-named clusters of different accounts keep their own releases
+	graph := ReportGraph(report, false)
+	r.Len(graph.Nodes, 9) // 6 resources, the accounts and the org
+	r.Contains(graph.Edges, GraphEdge{From: "eks|aws:111|prod", To: "helm|aws:111|eks:prod|ingress"})
+	r.Contains(graph.Edges, GraphEdge{From: "eks|aws:222|prod", To: "helm|aws:222|eks:prod|ingress"})
+	r.NotContains(graph.Edges, GraphEdge{From: "eks|aws:111|prod", To: "helm|aws:222|eks:prod|ingress"})
+	r.NotContains(graph.Edges, GraphEdge{From: "aws:111", To: "helm|aws:111|eks:prod|ingress"})
+
+	// The TFC resource of both the workspace and the account is counted once, the account is linked to
+	// the summary counting it
+	collapsed := ReportGraph(report, true)
+	total := 0
+	for _, node := range collapsed.Nodes {
+		total += node.Collapsed
+	}
+	r.Equal(4, total)
+	r.Contains(collapsed.Edges, GraphEdge{From: "aws:111", To: "tfc-workspace|tfc-org:org|infra#valid"})
+	r.Equal("2 VALID resources", collapsed.Nodes[len(collapsed.Nodes)-1].Name) // the cluster and release of 222
+}
@@ -0,0 +1,145 @@
+package util
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+)
+
+// Unowned is the group of resources without an owner
+const Unowned = "(unowned)"
+
+// NoValue is the group of resources missing the field they are grouped by
+const NoValue = "(none)"
+
+// ResourceGroup is a part of a report which shares the value of the field it was grouped by
+type ResourceGroup struct {
+	Key    string                `json:"key"`
+	Report types.InventoryReport `json:"report"`
+}
+
+// GroupedReport is a report split by a field, which keeps the identity, errors and stats of the report
+type GroupedReport struct {
+	Identity types.Indentity     `json:"identity,omitempty"`
+	GroupBy  string              `json:"group_by"`
+	Groups   []ResourceGroup     `json:"groups"`
+	Errors   []types.ScrapeError `json:"errors,omitempty"`
+	Stats    []types.ScrapeStat  `json:"stats,omitempty"`
+}
+
+// StatusCounts counts the resources of the group by status
+func (g ResourceGroup) StatusCounts() map[types.Status]int {
+	counts := map[types.Status]int{}
+	for _, item := range g.Report.Resources {
+		counts[item.GetVersionedResource().EOL.Status]++
+	}
+	return counts
+}
+
+type groupKey func(report types.InventoryReport, item types.VersionedResource) string
+
+var groupFields = map[string]groupKey{
+	"owner": func(report types.InventoryReport, item types.VersionedResource) string {
+		if len(item.Owner) == 0 {
+			return Unowned
+		}
+		return item.Owner
+	},
+	"kind": func(report types.InventoryReport, item types.VersionedResource) string {
+		return string(item.Kind)
+	},
+	// parent is the closest parent, e.g. the cluster of a helm release
+	"parent": func(report types.InventoryReport, item types.VersionedResource) string {
+		if len(item.Parents) == 0 {
+			return NoValue
+		}
+		return FormatParents(item.Parents[len(item.Parents)-1:])
+	},
+	// account falls back to the account of the report, for resources like helm releases
+	"account": func(report types.InventoryReport, item types.VersionedResource) string {
+		if account := AccountOf(item); len(account) > 0 {
+			return account
+		}
+		if len(report.Identity.AwsAccountNumber) > 0 {
+			return report.Identity.AwsAccountNumber
+		}
+		return NoValue
+	},
+	"status": func(report types.InventoryReport, item types.VersionedResource) string {
+		if len(item.EOL.Status) == 0 {
+			return NoValue
+		}
+		return string(item.EOL.Status)
+	},
+}
+
+// AccountOf returns the AWS account among the parents of a resource
+func AccountOf(item types.VersionedResource) string {
+	for _, p := range item.Parents {
+		if p.Kind == types.KindAWSAccount {
+			return p.ID
+		}
+	}
+	return ""
+}
+
+// GroupFields lists the fields reports can be grouped by
+func GroupFields() []string {
+	fields := []string{}
+	for field := range groupFields {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return append(fields, "tag.<key>")
+}
+
+func getGroupKey(field string) (groupKey, bool) {
+	if tag, ok := strings.CutPrefix(field, "tag."); ok && len(tag) > 0 {
+		return func(report types.InventoryReport, item types.VersionedResource) string {
+			if value, ok := item.Labels[tag]; ok {
+				return value
+			}
+			return NoValue
+		}, true
+	}
+	key, ok := groupFields[field]
+	return key, ok
+}
+
+// GroupReport splits the resources of a report by a field. Groups are sorted by key, status groups by
+// severity, with resources missing the field last.
+func GroupReport(report types.InventoryReport, field string) ([]ResourceGroup, error) {
+	key, ok := getGroupKey(field)
+	if !ok {
+		return nil, fmt.Errorf("unable to group by %q, supported fields are: %s", field, strings.Join(GroupFields(), ", "))
+	}
+
+	groups := []ResourceGroup{}
+	index := map[string]int{}
+	for _, item := range report.Resources {
+		k := key(report, item.GetVersionedResource())
+		i, ok := index[k]
+		if !ok {
+			i = len(groups)
+			index[k] = i
+			groups = append(groups, ResourceGroup{Key: k, Report: types.InventoryReport{Identity: report.Identity}})
+		}
+		groups[i].Report.Resources = append(groups[i].Report.Resources, item)
+	}
+
+	missing := func(key string) bool {
+		return key == Unowned || key == NoValue
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		if missing(groups[i].Key) != missing(groups[j].Key) {
+			return missing(groups[j].Key)
+		}
+		if field == "status" {
+			return statusSeverity(types.Status(groups[i].Key)) > statusSeverity(types.Status(groups[j].Key))
+		}
+		return groups[i].Key < groups[j].Key
+	})
+	return groups, nil
+}
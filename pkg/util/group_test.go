@@ -0,0 +1,56 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+	"github.com/stretchr/testify/require"
+)
+
+func groupKeys(groups []ResourceGroup) []string {
+	keys := []string{}
+	for _, group := range groups {
+		keys = append(keys, group.Key)
+	}
+	return keys
+}
+
+func TestGroupReport(t *testing.T) {
+	r := require.New(t)
+	report := *expressionReport()
+	report.Resources = append(report.Resources, types.HelmRelease{VersionedResource: types.VersionedResource{Kind: types.KindHelmRelease, ID: "ingress",
+		Parents: []types.ParentResource{{Kind: types.KindEKSCluster, ID: "prod"}}}})
+
+	groups, err := GroupReport(report, "status")
+	r.NoError(err)
+	r.Equal([]string{"CRITICAL", "WARNING", "VALID", NoValue}, groupKeys(groups))
+	r.Len(groups[0].Report.Resources, 2)
+	r.Equal(map[types.Status]int{types.StatusCritical: 2}, groups[0].StatusCounts())
+
+	groups, err = GroupReport(report, "account")
+	r.NoError(err)
+	r.Equal([]string{"prod-account", "sandbox-account", NoValue}, groupKeys(groups))
+	report.Identity.AwsAccountNumber = "prod-account"
+	groups, err = GroupReport(report, "account")
+	r.NoError(err)
+	r.Equal([]string{"prod-account", "sandbox-account"}, groupKeys(groups))
+
+	groups, err = GroupReport(report, "parent")
+	r.NoError(err)
+	r.Equal([]string{"aws:prod-account", "aws:sandbox-account", "eks:prod"}, groupKeys(groups))
+
+	groups, err = GroupReport(report, "kind")
+	r.NoError(err)
+	r.Equal([]string{"eks", "helm", "lambda", "rds"}, groupKeys(groups))
+
+	groups, err = GroupReport(report, "tag.team")
+	r.NoError(err)
+	r.Equal([]string{"platform", NoValue}, groupKeys(groups))
+
+	groups, err = GroupReport(report, "owner")
+	r.NoError(err)
+	r.Equal([]string{Unowned}, groupKeys(groups))
+
+	_, err = GroupReport(report, "region")
+	r.Error(err)
+}
@@ -0,0 +1,90 @@
+package util
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// Endpoints are the base URLs of the external APIs camelot talks to. They can be pointed at internal
+// mirrors, or at local stand-ins in tests.
+type Endpoints struct {
+	EndOfLife         string `yaml:"endoflife,omitempty"`
+	ArtifactHub       string `yaml:"artifacthub,omitempty"`
+	TerraformRegistry string `yaml:"terraform_registry,omitempty"`
+	GithubAPI         string `yaml:"github_api,omitempty"`
+	S3                string `yaml:"s3,omitempty"` // S3-compatible storage of --output-file, AWS when empty
+}
+
+var DefaultEndpoints = Endpoints{
+	EndOfLife:         "https://endoflife.date/api",
+	ArtifactHub:       "https://artifacthub.io/api/v1",
+	TerraformRegistry: "https://registry.terraform.io/v1",
+	GithubAPI:         "https://api.github.com",
+}
+
+var (
+	httpMutex  sync.RWMutex
+	endpoints  = DefaultEndpoints
+	httpClient = newHTTPClient(http.ProxyFromEnvironment)
+)
+
+// SetEndpoints overrides the API base URLs; blank endpoints fall back to the defaults
+func SetEndpoints(e Endpoints) {
+	e.EndOfLife = endpointOrDefault(e.EndOfLife, DefaultEndpoints.EndOfLife)
+	e.ArtifactHub = endpointOrDefault(e.ArtifactHub, DefaultEndpoints.ArtifactHub)
+	e.TerraformRegistry = endpointOrDefault(e.TerraformRegistry, DefaultEndpoints.TerraformRegistry)
+	e.GithubAPI = endpointOrDefault(e.GithubAPI, DefaultEndpoints.GithubAPI)
+	e.S3 = endpointOrDefault(e.S3, DefaultEndpoints.S3)
+
+	httpMutex.Lock()
+	defer httpMutex.Unlock()
+	endpoints = e
+}
+
+func GetEndpoints() Endpoints {
+	httpMutex.RLock()
+	defer httpMutex.RUnlock()
+	return endpoints
+}
+
+// SetHTTPClient replaces the client used for all outbound API calls
+func SetHTTPClient(client *http.Client) {
+	httpMutex.Lock()
+	defer httpMutex.Unlock()
+	httpClient = client
+}
+
+func HTTPClient() *http.Client {
+	httpMutex.RLock()
+	defer httpMutex.RUnlock()
+	return httpClient
+}
+
+// NewHTTPClient creates a client that sends requests through proxyURL, or through the proxy
+// configured in the environment (HTTP_PROXY, HTTPS_PROXY, NO_PROXY) when proxyURL is empty.
+func NewHTTPClient(proxyURL string) (*http.Client, error) {
+	if len(proxyURL) == 0 {
+		return newHTTPClient(http.ProxyFromEnvironment), nil
+	}
+	p, err := url.Parse(proxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy url %s: %w", proxyURL, err)
+	}
+	return newHTTPClient(http.ProxyURL(p)), nil
+}
+
+func newHTTPClient(proxy func(*http.Request) (*url.URL, error)) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = proxy
+	return &http.Client{Transport: transport}
+}
+
+func endpointOrDefault(endpoint, defaultEndpoint string) string {
+	if len(endpoint) == 0 {
+		return defaultEndpoint
+	}
+	return strings.TrimSuffix(endpoint, "/")
+}
@@ -0,0 +1,61 @@
+package util
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetEndpoints(t *testing.T) {
+	r := require.New(t)
+	defer SetEndpoints(DefaultEndpoints)
+
+	SetEndpoints(Endpoints{EndOfLife: "http://mirror.internal/eol/"})
+	e := GetEndpoints()
+	r.Equal("http://mirror.internal/eol", e.EndOfLife)
+	r.Equal(DefaultEndpoints.ArtifactHub, e.ArtifactHub)
+	r.Equal(DefaultEndpoints.TerraformRegistry, e.TerraformRegistry)
+	r.Equal(DefaultEndpoints.GithubAPI, e.GithubAPI)
+}
+
+func TestNewHTTPClient(t *testing.T) {
+	r := require.New(t)
+
+	client, err := NewHTTPClient("http://proxy.internal:3128")
+	r.NoError(err)
+	req, err := http.NewRequest("GET", "https://endoflife.date/api/amazon-eks.json", nil)
+	r.NoError(err)
+	p, err := client.Transport.(*http.Transport).Proxy(req)
+	r.NoError(err)
+	r.Equal("http://proxy.internal:3128", p.String())
+
+	_, err = NewHTTPClient("://invalid")
+	r.Error(err)
+}
+
+func TestLoadConfig(t *testing.T) {
+	r := require.New(t)
+
+	path := filepath.Join(t.TempDir(), "camelot.yaml")
+	err := os.WriteFile(path, []byte(`
+endpoints:
+  endoflife: http://mirror.internal/eol
+  github_api: https://github.example.com/api/v3
+proxy: http://proxy.internal:3128
+`), 0644)
+	r.NoError(err)
+
+	config, err := LoadConfig(path)
+	r.NoError(err)
+	r.Equal("http://mirror.internal/eol", config.Endpoints.EndOfLife)
+	r.Equal("https://github.example.com/api/v3", config.Endpoints.GithubAPI)
+	r.Equal("http://proxy.internal:3128", config.Proxy)
+
+	err = os.WriteFile(path, []byte("endpoint:\n  endoflife: http://mirror.internal/eol\n"), 0644)
+	r.NoError(err)
+	_, err = LoadConfig(path)
+	r.Error(err)
+}
@@ -0,0 +1,137 @@
+package util
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+)
+
+// JUnitTestSuites is a JUnit XML report, as read by CI systems
+type JUnitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Suites   []JUnitTestSuite `xml:"testsuite"`
+}
+
+type JUnitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	TestCases []JUnitTestCase `xml:"testcase"`
+}
+
+type JUnitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *JUnitMessage `xml:"failure,omitempty"`
+	Error     *JUnitMessage `xml:"error,omitempty"`
+	Skipped   *JUnitMessage `xml:"skipped,omitempty"`
+}
+
+type JUnitMessage struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Text    string `xml:",chardata"`
+}
+
+// ReportToJUnit maps each resource to a test case, in a suite per kind, which fails when the resource is
+// WARNING or CRITICAL and is skipped when it has no status. Scrape errors are errored test cases of an
+// extra "scrape" suite, so an incomplete inventory does not pass silently.
+func ReportToJUnit(report types.InventoryReport) JUnitTestSuites {
+	suites := JUnitTestSuites{Name: "camelot"}
+	kinds, _ := GroupReport(report, "kind")
+	for _, kind := range kinds {
+		suite := JUnitTestSuite{Name: kind.Key}
+		for _, item := range kind.Report.Resources {
+			resource := item.GetVersionedResource()
+			testCase := JUnitTestCase{Name: resource.ID, ClassName: junitClassName(resource)}
+			switch resource.EOL.Status {
+			case types.StatusWarning, types.StatusCritical:
+				testCase.Failure = &JUnitMessage{
+					Message: fmt.Sprintf("%s %s is %s", resource.ID, resource.Version, resource.EOL.Status),
+					Type:    string(resource.EOL.Status),
+					Text:    junitDetails(resource),
+				}
+				suite.Failures++
+			case types.StatusValid:
+			default:
+				testCase.Skipped = &JUnitMessage{Message: "no end of life data"}
+				suite.Skipped++
+			}
+			suite.TestCases = append(suite.TestCases, testCase)
+		}
+		suite.Tests = len(suite.TestCases)
+		suites.Suites = append(suites.Suites, suite)
+	}
+
+	if len(report.Errors) > 0 {
+		suite := JUnitTestSuite{Name: "scrape"}
+		for _, row := range ErrorsToTable(report) {
+			// Source, extractor, account, region, resource and message
+			name := strings.Join(nonEmpty(row[1:5]), "/")
+			if len(name) == 0 {
+				name = row[0]
+			}
+			suite.TestCases = append(suite.TestCases, JUnitTestCase{
+				Name:      name,
+				ClassName: row[0],
+				Error:     &JUnitMessage{Message: row[5], Type: "ScrapeError"},
+			})
+		}
+		suite.Tests = len(suite.TestCases)
+		suite.Errors = suite.Tests
+		suites.Suites = append(suites.Suites, suite)
+	}
+
+	for _, suite := range suites.Suites {
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Errors += suite.Errors
+	}
+	return suites
+}
+
+// junitClassName qualifies a resource by its kind and parents, e.g. helm.aws:123,eks:prod
+func junitClassName(resource types.VersionedResource) string {
+	if len(resource.Parents) == 0 {
+		return string(resource.Kind)
+	}
+	return string(resource.Kind) + "." + FormatParents(resource.Parents)
+}
+
+func junitDetails(resource types.VersionedResource) string {
+	details := []string{}
+	add := func(name, value string) {
+		if len(value) > 0 {
+			details = append(details, name+": "+value)
+		}
+	}
+	add("version", resource.Version)
+	add("current version", resource.CurrentVersion)
+	add("end of life", resource.EOL.EOLDate)
+	if len(resource.EOL.EOLDate) > 0 {
+		add("remaining days", fmt.Sprint(resource.EOL.RemainingDays))
+	}
+	add("upgrade path", FormatUpgradePath(resource.Remediation))
+	add("owner", resource.Owner)
+	add("arn", resource.Arn)
+	add("file", resource.GitOpsReference.File)
+	return strings.Join(details, "\n")
+}
+
+func nonEmpty(values []string) []string {
+	result := []string{}
+	for _, v := range values {
+		if len(v) > 0 {
+			result = append(result, v)
+		}
+	}
+	return result
+}
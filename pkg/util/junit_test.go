@@ -0,0 +1,36 @@
+package util
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportToJUnit(t *testing.T) {
+	r := require.New(t)
+	report := *expressionReport()
+	report.Resources = append(report.Resources, types.HelmRelease{VersionedResource: types.VersionedResource{Kind: types.KindHelmRelease, ID: "ingress"}})
+	report.Errors = []types.ScrapeError{{Source: "aws", Extractor: "rds", Account: "prod-account", Message: "access denied"}}
+
+	suites := ReportToJUnit(report)
+	r.Equal([]string{"eks", "helm", "lambda", "rds", "scrape"},
+		[]string{suites.Suites[0].Name, suites.Suites[1].Name, suites.Suites[2].Name, suites.Suites[3].Name, suites.Suites[4].Name})
+	r.Equal(6, suites.Tests)
+	r.Equal(3, suites.Failures)
+	r.Equal(1, suites.Errors)
+
+	eks := suites.Suites[0]
+	r.Equal(2, eks.Tests)
+	r.Equal(2, eks.Failures)
+	r.Equal("eks.aws:prod-account", eks.TestCases[0].ClassName)
+	r.Equal("CRITICAL", eks.TestCases[0].Failure.Type)
+	r.Contains(eks.TestCases[0].Failure.Text, "version: 1.24")
+	r.Equal(1, suites.Suites[1].Skipped)
+	r.Equal("rds/prod-account", suites.Suites[4].TestCases[0].Name)
+
+	b, err := xml.Marshal(suites)
+	r.NoError(err)
+	r.Contains(string(b), `<testsuites name="camelot" tests="6" failures="3" errors="1">`)
+}
@@ -0,0 +1,47 @@
+package util
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+)
+
+// repoIdentifier matches VCS repo identifiers like chanzuckerberg/camelot, which are hosted on GitHub
+var repoIdentifier = regexp.MustCompile(`^[\w.-]+/[\w.-]+$`)
+
+// RepoURL returns the web URL of the repo of a GitOps reference, or "" when it cannot tell
+func RepoURL(ref types.GitOpsReference) string {
+	repo := strings.TrimSuffix(ref.Repo, ".git")
+	if strings.HasPrefix(repo, "https://") || strings.HasPrefix(repo, "http://") {
+		return repo
+	}
+	if repoIdentifier.MatchString(repo) {
+		return "https://github.com/" + repo
+	}
+	return ""
+}
+
+// PathURL returns the web URL of the path of a GitOps reference on its branch, or "" when it has no
+// path or repo URL
+func PathURL(ref types.GitOpsReference) string {
+	repo := RepoURL(ref)
+	if len(repo) == 0 || len(ref.Path) == 0 {
+		return ""
+	}
+	branch := ref.Branch
+	if len(branch) == 0 {
+		branch = "HEAD"
+	}
+	return repo + "/tree/" + branch + "/" + strings.TrimPrefix(ref.Path, "/")
+}
+
+// ConsoleURL returns the AWS console URL which redirects to the page of a resource, or "" when it has
+// no ARN
+func ConsoleURL(arn string) string {
+	if !strings.HasPrefix(arn, "arn:") {
+		return ""
+	}
+	return "https://console.aws.amazon.com/go/view?arn=" + url.QueryEscape(arn)
+}
@@ -0,0 +1,28 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLinks(t *testing.T) {
+	r := require.New(t)
+
+	ref := types.GitOpsReference{Repo: "https://github.com/chanzuckerberg/infra.git", Branch: "prod", Path: "/envs/prod"}
+	r.Equal("https://github.com/chanzuckerberg/infra", RepoURL(ref))
+	r.Equal("https://github.com/chanzuckerberg/infra/tree/prod/envs/prod", PathURL(ref))
+
+	ref = types.GitOpsReference{Repo: "chanzuckerberg/camelot", Path: "terraform"}
+	r.Equal("https://github.com/chanzuckerberg/camelot", RepoURL(ref))
+	r.Equal("https://github.com/chanzuckerberg/camelot/tree/HEAD/terraform", PathURL(ref))
+
+	r.Empty(RepoURL(types.GitOpsReference{Repo: "/tmp/checkout"}))
+	r.Empty(PathURL(types.GitOpsReference{Path: "terraform"}))
+	r.Empty(PathURL(types.GitOpsReference{Repo: "chanzuckerberg/camelot"}))
+
+	r.Equal("https://console.aws.amazon.com/go/view?arn=arn%3Aaws%3Aeks%3Aus-west-2%3A123%3Acluster%2Fprod",
+		ConsoleURL("arn:aws:eks:us-west-2:123:cluster/prod"))
+	r.Empty(ConsoleURL(""))
+}
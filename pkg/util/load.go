@@ -0,0 +1,58 @@
+package util
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+)
+
+// LoadReport reads a report saved with `-o json`, combining the reports of a file holding several of
+// them; "-" reads it from stdin
+func LoadReport(path string) (*types.InventoryReport, error) {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("unable to open report %s: %w", path, err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	// Commands printing a report per account, like scrape aws --all -o json, write one document each
+	reports := []*types.InventoryReport{}
+	decoder := json.NewDecoder(r)
+	for {
+		report := &types.InventoryReport{}
+		err := decoder.Decode(report)
+		if errors.Is(err, io.EOF) && len(reports) > 0 {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode report %s: %w", path, err)
+		}
+		reports = append(reports, report)
+	}
+	if len(reports) == 1 {
+		return reports[0], nil
+	}
+	combined := CombineReports(reports)
+	combined.Identity = CommonIdentity(reports)
+	return &combined, nil
+}
+
+func LoadReports(paths []string) ([]*types.InventoryReport, error) {
+	reports := []*types.InventoryReport{}
+	for _, path := range paths {
+		report, err := LoadReport(path)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
@@ -0,0 +1,67 @@
+package util
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadReports(t *testing.T) {
+	r := require.New(t)
+	dir := t.TempDir()
+
+	report := types.InventoryReport{
+		Identity: types.Indentity{AwsAccountNumber: "123456789012"},
+		Resources: []types.Versioned{
+			types.Lambda{VersionedResource: types.VersionedResource{Kind: types.KindLambda, ID: "fn1"}, Engine: "python3.8"},
+		},
+	}
+	b, err := json.MarshalIndent(report, "", "  ")
+	r.NoError(err)
+	path := filepath.Join(dir, "report.json")
+	r.NoError(os.WriteFile(path, b, 0644))
+
+	reports, err := LoadReports([]string{path, path})
+	r.NoError(err)
+	r.Len(reports, 2)
+	r.Equal(report, *reports[0])
+
+	_, err = LoadReports([]string{path, filepath.Join(dir, "missing.json")})
+	r.Error(err)
+
+	invalid := filepath.Join(dir, "invalid.json")
+	r.NoError(os.WriteFile(invalid, []byte(`{"resources": [{"kind": "unknown"}]}`), 0644))
+	_, err = LoadReport(invalid)
+	r.Error(err)
+
+	// scrape aws --all -o json writes a document per account
+	other := types.InventoryReport{
+		Identity:  types.Indentity{AwsAccountNumber: "210987654321"},
+		Resources: []types.Versioned{types.Lambda{VersionedResource: types.VersionedResource{Kind: types.KindLambda, ID: "fn2"}, Engine: "nodejs20.x"}},
+		Errors:    []types.ScrapeError{{Source: "aws", Account: "210987654321", Message: "access denied"}},
+	}
+	b2, err := json.MarshalIndent(other, "", "  ")
+	r.NoError(err)
+	accounts := filepath.Join(dir, "accounts.json")
+	r.NoError(os.WriteFile(accounts, append(append(b, '\n'), b2...), 0644))
+	combined, err := LoadReport(accounts)
+	r.NoError(err)
+	r.Len(combined.Resources, 2)
+	r.Equal("fn2", combined.Resources[1].GetVersionedResource().ID)
+	r.Len(combined.Errors, 1)
+	r.Equal(types.Indentity{}, combined.Identity)
+
+	trailing := filepath.Join(dir, "trailing.json")
+	r.NoError(os.WriteFile(trailing, append(b, []byte("\n{\"resources\": [")...), 0644))
+	_, err = LoadReport(trailing)
+	r.Error(err)
+
+	empty := filepath.Join(dir, "empty.json")
+	r.NoError(os.WriteFile(empty, []byte{}, 0644))
+	_, err = LoadReport(empty)
+	r.Error(err)
+}
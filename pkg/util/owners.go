@@ -0,0 +1,181 @@
+package util
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+	"gopkg.in/yaml.v2"
+)
+
+// DefaultOwnerTags are the AWS tags, and TFC workspace tag prefixes, which name the owning team
+var DefaultOwnerTags = []string{"owner", "team"}
+
+var (
+	ownerTagsMutex sync.RWMutex
+	ownerTags      = DefaultOwnerTags
+)
+
+func SetOwnerTags(tags []string) {
+	ownerTagsMutex.Lock()
+	defer ownerTagsMutex.Unlock()
+	if len(tags) == 0 {
+		tags = DefaultOwnerTags
+	}
+	ownerTags = tags
+}
+
+func GetOwnerTags() []string {
+	ownerTagsMutex.RLock()
+	defer ownerTagsMutex.RUnlock()
+	return ownerTags
+}
+
+// OwnerFromLabels returns the value of the first owner tag found in labels
+func OwnerFromLabels(labels map[string]string) string {
+	for _, tag := range GetOwnerTags() {
+		if owner, ok := labels[tag]; ok && len(owner) > 0 {
+			return owner
+		}
+	}
+	return ""
+}
+
+// OwnerFromTags reads the owner from `key:value` or `key=value` tags, like TFC workspace tags
+func OwnerFromTags(tags []string) string {
+	labels := map[string]string{}
+	for _, tag := range tags {
+		key, value, ok := strings.Cut(tag, ":")
+		if !ok {
+			key, value, ok = strings.Cut(tag, "=")
+		}
+		if ok {
+			labels[key] = value
+		}
+	}
+	return OwnerFromLabels(labels)
+}
+
+// OwnerRule assigns an owner to resources which match all of its (glob) patterns
+type OwnerRule struct {
+	Owner  string            `yaml:"owner"`
+	Kind   string            `yaml:"kind,omitempty"`
+	ID     string            `yaml:"id,omitempty"`
+	Parent string            `yaml:"parent,omitempty"` // kind:id of any parent
+	Repo   string            `yaml:"repo,omitempty"`   // gitops reference repo
+	Labels map[string]string `yaml:"labels,omitempty"`
+}
+
+// OwnerMapping is the fallback for resources whose owner is not known from their tags, CODEOWNERS or
+// workspace; the first matching rule wins
+type OwnerMapping []OwnerRule
+
+func LoadOwnerMapping(file string) (OwnerMapping, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read owners file %s: %w", file, err)
+	}
+	mapping := OwnerMapping{}
+	err = yaml.UnmarshalStrict(b, &mapping)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse owners file %s: %w", file, err)
+	}
+	for i, rule := range mapping {
+		if len(rule.Owner) == 0 {
+			return nil, fmt.Errorf("rule %d in owners file %s has no owner", i, file)
+		}
+		for _, pattern := range []string{rule.Kind, rule.ID, rule.Parent, rule.Repo} {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("rule %d in owners file %s has an invalid pattern %q: %w", i, file, pattern, err)
+			}
+		}
+	}
+	return mapping, nil
+}
+
+func (m OwnerMapping) Owner(item types.VersionedResource) string {
+	for _, rule := range m {
+		if rule.matches(item) {
+			return rule.Owner
+		}
+	}
+	return ""
+}
+
+func (rule OwnerRule) matches(item types.VersionedResource) bool {
+	glob := func(pattern, value string) bool {
+		if len(pattern) == 0 {
+			return true
+		}
+		matched, _ := path.Match(pattern, value)
+		return matched
+	}
+
+	if !glob(rule.Kind, string(item.Kind)) || !glob(rule.ID, item.ID) || !glob(rule.Repo, item.GitOpsReference.Repo) {
+		return false
+	}
+	if len(rule.Parent) > 0 {
+		found := false
+		for _, p := range item.Parents {
+			if glob(rule.Parent, string(p.Kind)+":"+p.ID) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	for key, value := range rule.Labels {
+		if !glob(value, item.Labels[key]) {
+			return false
+		}
+	}
+	return true
+}
+
+// ResolveOwners assigns owners to the resources the scrapers could not attribute: from their tags first,
+// then from the closest parent in the report with an owner, and finally from the mapping (whose owners
+// are passed on to children in turn)
+func ResolveOwners(report *types.InventoryReport, mapping OwnerMapping) {
+	if report == nil {
+		return
+	}
+	report.UpdateResources(func(resource *types.VersionedResource) {
+		if len(resource.Owner) == 0 {
+			resource.Owner = OwnerFromLabels(resource.Labels)
+		}
+	})
+	inheritOwners(report)
+	report.UpdateResources(func(resource *types.VersionedResource) {
+		if len(resource.Owner) == 0 {
+			resource.Owner = mapping.Owner(*resource)
+		}
+	})
+	inheritOwners(report)
+}
+
+func inheritOwners(report *types.InventoryReport) {
+	owners := map[types.ParentResource]string{}
+	for _, item := range report.Resources {
+		resource := item.GetVersionedResource()
+		if len(resource.Owner) > 0 {
+			owners[types.ParentResource{Kind: resource.Kind, ID: resource.ID}] = resource.Owner
+		}
+	}
+
+	report.UpdateResources(func(resource *types.VersionedResource) {
+		if len(resource.Owner) > 0 {
+			return
+		}
+		for i := len(resource.Parents) - 1; i >= 0; i-- {
+			if owner, ok := owners[resource.Parents[i]]; ok {
+				resource.Owner = owner
+				return
+			}
+		}
+	})
+}
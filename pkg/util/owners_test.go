@@ -0,0 +1,66 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOwnerFromTags(t *testing.T) {
+	r := require.New(t)
+	r.Equal("payments", OwnerFromLabels(map[string]string{"team": "payments", "env": "prod"}))
+	r.Equal("alice", OwnerFromLabels(map[string]string{"team": "payments", "owner": "alice"}))
+	r.Equal("", OwnerFromLabels(nil))
+	r.Equal("payments", OwnerFromTags([]string{"prod", "team:payments"}))
+	r.Equal("infra", OwnerFromTags([]string{"owner=infra"}))
+
+	SetOwnerTags([]string{"squad"})
+	defer SetOwnerTags(nil)
+	r.Equal("", OwnerFromLabels(map[string]string{"team": "payments"}))
+	r.Equal("growth", OwnerFromLabels(map[string]string{"squad": "growth"}))
+}
+
+func TestResolveOwners(t *testing.T) {
+	r := require.New(t)
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "owners.yaml")
+	r.NoError(os.WriteFile(path, []byte(`
+- owner: data
+  kind: rds
+  id: "analytics-*"
+- owner: platform
+  parent: "aws:123456789012"
+`), 0644))
+	mapping, err := LoadOwnerMapping(path)
+	r.NoError(err)
+	r.Len(mapping, 2)
+
+	account := []types.ParentResource{{Kind: types.KindAWSAccount, ID: "123456789012"}}
+	report := &types.InventoryReport{Resources: []types.Versioned{
+		types.EKSCluster{VersionedResource: types.VersionedResource{Kind: types.KindEKSCluster, ID: "tagged", Parents: account, Labels: map[string]string{"team": "payments"}}},
+		types.HelmRelease{VersionedResource: types.VersionedResource{Kind: types.KindHelmRelease, ID: "release", Parents: []types.ParentResource{{Kind: types.KindEKSCluster, ID: "tagged"}}}},
+		types.EKSCluster{VersionedResource: types.VersionedResource{Kind: types.KindEKSCluster, ID: "untagged", Parents: account}},
+		types.HelmRelease{VersionedResource: types.VersionedResource{Kind: types.KindHelmRelease, ID: "release", Parents: []types.ParentResource{{Kind: types.KindEKSCluster, ID: "untagged"}}}},
+		types.RDSCluster{VersionedResource: types.VersionedResource{Kind: types.KindRDSCluster, ID: "analytics-db", Parents: account}},
+		types.GitRepo{VersionedResource: types.VersionedResource{Kind: types.KindGithubRepo, ID: "repo", Owner: "infra", Labels: map[string]string{"team": "payments"}}},
+		types.Lambda{VersionedResource: types.VersionedResource{Kind: types.KindLambda, ID: "orphan"}},
+	}}
+	ResolveOwners(report, mapping)
+
+	owners := []string{}
+	for _, item := range report.Resources {
+		owners = append(owners, item.GetVersionedResource().Owner)
+	}
+	r.Equal([]string{"payments", "payments", "platform", "platform", "data", "infra", ""}, owners)
+
+	r.NoError(os.WriteFile(path, []byte(`[{kind: eks}]`), 0644))
+	_, err = LoadOwnerMapping(path)
+	r.Error(err)
+	r.NoError(os.WriteFile(path, []byte(`[{owner: x, id: "[a"}]`), 0644))
+	_, err = LoadOwnerMapping(path)
+	r.Error(err)
+}
@@ -0,0 +1,165 @@
+package util
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+)
+
+// KindDefault is the policy entry used for resource kinds without thresholds of their own
+const KindDefault types.ResourceKind = "default"
+
+// Threshold is the number of remaining days at (or below) which a resource turns WARNING or CRITICAL.
+// A negative threshold disables the status.
+type Threshold struct {
+	Warning  int `json:"warning"`
+	Critical int `json:"critical"`
+}
+
+// Policy maps resource kinds to their status thresholds
+type Policy map[types.ResourceKind]Threshold
+
+var DefaultPolicy = Policy{
+	KindDefault: {Warning: 90, Critical: 30},
+	// Repos are assumed to be supported for 3 years after the last commit
+	types.KindGithubRepo: {Warning: 0, Critical: -1},
+	// Workspaces are assumed to be supported for 3 months after the last update
+	types.KindTFCWorkspace: {Warning: 0, Critical: -1},
+	// Only imported certificates are judged by their expiration, ACM renews the rest
+	types.KindACMCertificate: {Warning: 30, Critical: -1},
+}
+
+var (
+	policyMutex  sync.RWMutex
+	activePolicy = DefaultPolicy
+)
+
+func (p Policy) Threshold(kind types.ResourceKind) Threshold {
+	if threshold, ok := p[kind]; ok {
+		return threshold
+	}
+	if threshold, ok := p[KindDefault]; ok {
+		return threshold
+	}
+	return DefaultPolicy[KindDefault]
+}
+
+func (p Policy) Status(kind types.ResourceKind, remainingDays int) types.Status {
+	threshold := p.Threshold(kind)
+	if threshold.Critical >= 0 && remainingDays <= threshold.Critical {
+		return types.StatusCritical
+	}
+	if threshold.Warning >= 0 && remainingDays <= threshold.Warning {
+		return types.StatusWarning
+	}
+	return types.StatusValid
+}
+
+func SetPolicy(p Policy) {
+	policyMutex.Lock()
+	defer policyMutex.Unlock()
+	activePolicy = p
+}
+
+func GetPolicy() Policy {
+	policyMutex.RLock()
+	defer policyMutex.RUnlock()
+	return activePolicy
+}
+
+// ThresholdConfig is the config file form of a threshold, e.g. `eks: {warn: 180d, critical: 60d}`.
+// Omitted thresholds keep their defaults.
+type ThresholdConfig struct {
+	Warning  *Days `yaml:"warn,omitempty"`
+	Critical *Days `yaml:"critical,omitempty"`
+}
+
+// NewPolicy applies per-kind thresholds on top of the default policy. The `default` entry applies to
+// kinds which have no built-in thresholds of their own. Entries for unknown kinds are rejected.
+func NewPolicy(config map[types.ResourceKind]ThresholdConfig) (Policy, error) {
+	unknown := []string{}
+	for kind := range config {
+		if kind != KindDefault && !slices.Contains(types.ResourceKinds, kind) {
+			unknown = append(unknown, string(kind))
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		kinds := []string{string(KindDefault)}
+		for _, kind := range types.ResourceKinds {
+			kinds = append(kinds, string(kind))
+		}
+		return nil, fmt.Errorf("unknown resource kind %q in the policy, expected one of: %s", unknown[0], strings.Join(kinds, ", "))
+	}
+
+	policy := Policy{}
+	for kind, threshold := range DefaultPolicy {
+		policy[kind] = threshold
+	}
+	apply := func(kind types.ResourceKind, c ThresholdConfig) {
+		threshold := policy.Threshold(kind)
+		if c.Warning != nil {
+			threshold.Warning = int(*c.Warning)
+		}
+		if c.Critical != nil {
+			threshold.Critical = int(*c.Critical)
+		}
+		policy[kind] = threshold
+	}
+
+	// The default goes first, so that other kinds build on top of it
+	if c, ok := config[KindDefault]; ok {
+		apply(KindDefault, c)
+	}
+	for kind, c := range config {
+		if kind != KindDefault {
+			apply(kind, c)
+		}
+	}
+	return policy, nil
+}
+
+// Days is a number of days, written as `45`, `45d`, `6w` or `1y`; `off` disables a threshold
+type Days int
+
+func (d *Days) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	err := unmarshal(&s)
+	if err != nil {
+		return err
+	}
+	days, err := ParseDays(s)
+	if err != nil {
+		return err
+	}
+	*d = days
+	return nil
+}
+
+func ParseDays(days string) (Days, error) {
+	s := strings.ToLower(strings.TrimSpace(days))
+	if s == "off" || s == "none" {
+		return -1, nil
+	}
+	multiplier := 1
+	switch {
+	case strings.HasSuffix(s, "d"):
+		s = strings.TrimSuffix(s, "d")
+	case strings.HasSuffix(s, "w"):
+		s = strings.TrimSuffix(s, "w")
+		multiplier = 7
+	case strings.HasSuffix(s, "y"):
+		s = strings.TrimSuffix(s, "y")
+		multiplier = 365
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number of days %q", days)
+	}
+	return Days(n * multiplier), nil
+}
@@ -0,0 +1,73 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func TestDefaultPolicy(t *testing.T) {
+	r := require.New(t)
+
+	r.Equal(types.Status(types.StatusCritical), DefaultPolicy.Status(types.KindEKSCluster, 30))
+	r.Equal(types.Status(types.StatusWarning), DefaultPolicy.Status(types.KindEKSCluster, 31))
+	r.Equal(types.Status(types.StatusValid), DefaultPolicy.Status(types.KindEKSCluster, 91))
+
+	r.Equal(types.Status(types.StatusWarning), DefaultPolicy.Status(types.KindGithubRepo, 0))
+	r.Equal(types.Status(types.StatusValid), DefaultPolicy.Status(types.KindGithubRepo, 1))
+	r.Equal(types.Status(types.StatusWarning), DefaultPolicy.Status(types.KindACMCertificate, 10))
+}
+
+func TestNewPolicy(t *testing.T) {
+	r := require.New(t)
+
+	config := map[types.ResourceKind]ThresholdConfig{}
+	err := yaml.Unmarshal([]byte(`
+default: {critical: 14}
+eks: {warn: 180d, critical: 60d}
+cert: {warn: 45d, critical: 2w}
+github-repo: {warn: "off"}
+`), &config)
+	r.NoError(err)
+
+	policy, err := NewPolicy(config)
+	r.NoError(err)
+	r.Equal(Threshold{Warning: 180, Critical: 60}, policy.Threshold(types.KindEKSCluster))
+	r.Equal(Threshold{Warning: 45, Critical: 14}, policy.Threshold(types.KindACMCertificate))
+	r.Equal(Threshold{Warning: -1, Critical: -1}, policy.Threshold(types.KindGithubRepo))
+	r.Equal(Threshold{Warning: 90, Critical: 14}, policy.Threshold(types.KindRDSCluster))
+	r.Equal(Threshold{Warning: 0, Critical: -1}, policy.Threshold(types.KindTFCWorkspace))
+
+	r.Equal(types.Status(types.StatusWarning), policy.Status(types.KindEKSCluster, 150))
+	r.Equal(types.Status(types.StatusCritical), policy.Status(types.KindEKSCluster, 60))
+	r.Equal(types.Status(types.StatusValid), policy.Status(types.KindGithubRepo, 0))
+
+	// Defaults are left untouched
+	r.Equal(Threshold{Warning: 90, Critical: 30}, DefaultPolicy.Threshold(types.KindEKSCluster))
+
+	// Typos are not silently ignored
+	for _, kind := range []types.ResourceKind{"ekss", "helm-release", "aws"} {
+		_, err = NewPolicy(map[types.ResourceKind]ThresholdConfig{types.KindEKSCluster: {}, kind: {}})
+		r.ErrorContains(err, `"`+string(kind)+`"`)
+	}
+}
+
+func TestParseDays(t *testing.T) {
+	r := require.New(t)
+
+	for s, expected := range map[string]Days{"45": 45, "45d": 45, "6w": 42, "1y": 365, "off": -1, " 10D ": 10} {
+		days, err := ParseDays(s)
+		r.NoError(err)
+		r.Equal(expected, days, s)
+	}
+
+	_, err := ParseDays("3 months")
+	r.Error(err)
+
+	config := ThresholdConfig{}
+	r.NoError(yaml.Unmarshal([]byte("warn: 30"), &config))
+	r.Equal(Days(30), *config.Warning)
+	r.Nil(config.Critical)
+}
@@ -0,0 +1,129 @@
+package util
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+)
+
+// prometheusStatuses are the values of the status label of camelot_resource_status, one series each
+var prometheusStatuses = []types.Status{types.StatusValid, types.StatusWarning, types.StatusCritical}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+type metricFamily struct {
+	name   string
+	help   string
+	typ    string
+	series map[string]float64
+}
+
+// seriesKey renders label name and value pairs, e.g. kind="eks",id="prod"
+func seriesKey(labels []string) string {
+	var sb strings.Builder
+	for i := 0; i+1 < len(labels); i += 2 {
+		if sb.Len() > 0 {
+			sb.WriteString(",")
+		}
+		sb.WriteString(labels[i])
+		sb.WriteString(`="`)
+		sb.WriteString(labelEscaper.Replace(labels[i+1]))
+		sb.WriteString(`"`)
+	}
+	return sb.String()
+}
+
+// set records the value of a series, unless it was already set: the same resource reported twice (e.g.
+// on several branches) is a single series
+func (m *metricFamily) set(value float64, labels ...string) {
+	key := seriesKey(labels)
+	if _, ok := m.series[key]; !ok {
+		m.series[key] = value
+	}
+}
+
+func (m *metricFamily) add(value float64, labels ...string) {
+	m.series[seriesKey(labels)] += value
+}
+
+func (m *metricFamily) write(sb *strings.Builder) {
+	if len(m.series) == 0 {
+		return
+	}
+	fmt.Fprintf(sb, "# HELP %s %s\n# TYPE %s %s\n", m.name, m.help, m.name, m.typ)
+	keys := make([]string, 0, len(m.series))
+	for k := range m.series {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(sb, "%s{%s} %s\n", m.name, k, strconv.FormatFloat(m.series[k], 'g', -1, 64))
+	}
+}
+
+// ReportToPrometheus renders the report in the Prometheus text exposition format, as read by the node
+// exporter textfile collector: the remaining days and status of each resource, resource counts by kind
+// and status, and the duration, resource and error counts of the scrape.
+func ReportToPrometheus(report types.InventoryReport) string {
+	remainingDays := &metricFamily{name: "camelot_resource_remaining_days", typ: "gauge", series: map[string]float64{},
+		help: "Days until the end of life of a resource, for resources with an end of life date."}
+	status := &metricFamily{name: "camelot_resource_status", typ: "gauge", series: map[string]float64{},
+		help: "Status of a resource, 1 for its current status and 0 for the others."}
+	resources := &metricFamily{name: "camelot_resources", typ: "gauge", series: map[string]float64{},
+		help: "Number of resources by kind and status."}
+	duration := &metricFamily{name: "camelot_scrape_duration_seconds", typ: "gauge", series: map[string]float64{},
+		help: "Duration of the last scrape of a source."}
+	scraped := &metricFamily{name: "camelot_scrape_resources", typ: "gauge", series: map[string]float64{},
+		help: "Number of resources found by the last scrape of a source."}
+	scrapeErrors := &metricFamily{name: "camelot_scrape_errors", typ: "gauge", series: map[string]float64{},
+		help: "Number of errors of the last scrape of a source."}
+
+	for _, item := range report.Resources {
+		resource := item.GetVersionedResource()
+		account := AccountOf(resource)
+		if len(account) == 0 {
+			account = report.Identity.AwsAccountNumber
+		}
+		labels := []string{
+			"kind", string(resource.Kind),
+			"id", resource.ID,
+			"parent", FormatParents(resource.Parents),
+			"version", resource.Version,
+			"account", account,
+			"owner", resource.Owner,
+		}
+		if len(resource.EOL.EOLDate) > 0 {
+			remainingDays.set(float64(resource.EOL.RemainingDays), labels...)
+		}
+		for _, s := range prometheusStatuses {
+			value := 0.0
+			if resource.EOL.Status == s {
+				value = 1
+			}
+			status.set(value, append(labels, "status", string(s))...)
+		}
+		resources.add(1, "kind", string(resource.Kind), "status", string(resource.EOL.Status))
+	}
+
+	for _, stat := range report.Stats {
+		labels := []string{"source", stat.Source, "extractor", stat.Extractor, "account", stat.Account, "region", stat.Region}
+		duration.set(stat.Duration, labels...)
+		scraped.set(float64(stat.Resources), labels...)
+	}
+	// Errors are counted from the report, which has them even when it has no stats
+	for _, stat := range report.Stats {
+		scrapeErrors.set(0, "source", stat.Source, "extractor", stat.Extractor)
+	}
+	for _, e := range report.Errors {
+		scrapeErrors.add(1, "source", e.Source, "extractor", e.Extractor)
+	}
+
+	var sb strings.Builder
+	for _, family := range []*metricFamily{remainingDays, status, resources, duration, scraped, scrapeErrors} {
+		family.write(&sb)
+	}
+	return sb.String()
+}
@@ -0,0 +1,41 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportToPrometheus(t *testing.T) {
+	r := require.New(t)
+	account := []types.ParentResource{{Kind: types.KindAWSAccount, ID: "123"}}
+	prod := types.EKSCluster{VersionedResource: types.VersionedResource{Kind: types.KindEKSCluster, ID: "prod", Parents: account, Version: "1.27",
+		EOL: types.EOLStatus{EOLDate: "2024-07-24", RemainingDays: 45, Status: types.StatusCritical}}}
+	report := types.InventoryReport{
+		Identity: types.Indentity{AwsAccountNumber: "123"},
+		Resources: []types.Versioned{
+			prod,
+			prod,
+			types.HelmRelease{VersionedResource: types.VersionedResource{Kind: types.KindHelmRelease, ID: `say "hi"`, Version: "1.0.0",
+				EOL: types.EOLStatus{Status: types.StatusValid}}},
+		},
+		Errors: []types.ScrapeError{{Source: "aws", Extractor: "rds", Message: "denied"}, {Source: "aws", Extractor: "rds", Message: "denied"}},
+		Stats: []types.ScrapeStat{
+			{Source: "aws", Extractor: "eks", Account: "123", Region: "us-west-2", Duration: 1.5, Resources: 2},
+			{Source: "aws", Extractor: "rds", Account: "123", Region: "us-west-2", Duration: 0.25, Errors: 2},
+		},
+	}
+
+	metrics := ReportToPrometheus(report)
+	r.Contains(metrics, "# TYPE camelot_resource_remaining_days gauge\n"+
+		`camelot_resource_remaining_days{kind="eks",id="prod",parent="aws:123",version="1.27",account="123",owner=""} 45`+"\n#")
+	r.Contains(metrics, `camelot_resource_status{kind="eks",id="prod",parent="aws:123",version="1.27",account="123",owner="",status="CRITICAL"} 1`)
+	r.Contains(metrics, `camelot_resource_status{kind="eks",id="prod",parent="aws:123",version="1.27",account="123",owner="",status="VALID"} 0`)
+	r.Contains(metrics, `camelot_resource_status{kind="helm",id="say \"hi\"",parent="",version="1.0.0",account="123",owner="",status="VALID"} 1`)
+	r.NotContains(metrics, `camelot_resource_remaining_days{kind="helm"`)
+	r.Contains(metrics, `camelot_resources{kind="eks",status="CRITICAL"} 2`)
+	r.Contains(metrics, `camelot_scrape_duration_seconds{source="aws",extractor="eks",account="123",region="us-west-2"} 1.5`)
+	r.Contains(metrics, `camelot_scrape_errors{source="aws",extractor="eks"} 0`)
+	r.Contains(metrics, `camelot_scrape_errors{source="aws",extractor="rds"} 2`)
+}
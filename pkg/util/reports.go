@@ -10,25 +10,47 @@ import (
 )
 
 type ReportFilter struct {
-	ResourceKinds []types.ResourceKind // TODO: Support logical operators
+	ResourceKinds []types.ResourceKind
 	ParentKinds   []types.ResourceKind
 	ParentIDs     []string
 	IDs           []string
 	Status        []types.Status
 	Version       string
+	Labels        map[string][]string // tag.<key>=<value>, any of the values matches
+	Owners        []string            // an empty owner matches resources without one
+	Managed       *bool               // managed=true: in the state of a TFC workspace, managed=false: click-ops
+	Expressions   []Expression        // filter expressions, combined with the fields above with and
 }
 
-func ReportToTable(report types.InventoryReport) [][]string {
+// ReportToTable renders resources as table rows, followed by the values of the given tags
+func ReportToTable(report types.InventoryReport, tagColumns ...string) [][]string {
 	var table [][]string
 	for _, item := range report.Resources {
-		table = append(table, versionedResourceToTableRow(item.GetVersionedResource()))
+		table = append(table, versionedResourceToTableRow(item.GetVersionedResource(), tagColumns))
 	}
 	return table
 }
 
-func versionedResourceToTableRow(item types.VersionedResource) []string {
+func versionedResourceToTableRow(item types.VersionedResource, tagColumns []string) []string {
+	row := []string{
+		string(item.Kind),
+		truncate(item.ID, 40),
+		truncate(FormatParents(item.Parents), 80),
+		item.Version,
+		item.CurrentVersion,
+		string(item.EOL.Status),
+		item.EOL.EOLDate,
+	}
+	for _, tag := range tagColumns {
+		row = append(row, truncate(item.Labels[tag], 40))
+	}
+	return row
+}
+
+// FormatParents renders parents as kind:id pairs, e.g. eks:my-cluster,helm:my-release
+func FormatParents(parents []types.ParentResource) string {
 	var sb strings.Builder
-	for _, p := range item.Parents {
+	for _, p := range parents {
 		if sb.Len() > 0 {
 			sb.WriteString(",")
 		}
@@ -36,15 +58,15 @@ func versionedResourceToTableRow(item types.VersionedResource) []string {
 		sb.WriteString(":")
 		sb.WriteString(p.ID)
 	}
-	return []string{
-		string(item.Kind),
-		truncate(item.ID, 40),
-		truncate(sb.String(), 80),
-		item.Version,
-		item.CurrentVersion,
-		string(item.EOL.Status),
-		item.EOL.EOLDate,
+	return sb.String()
+}
+
+// FormatUpgradePath renders the versions a resource has to be upgraded through, e.g. 1.26 -> 1.27 -> 1.28
+func FormatUpgradePath(remediation *types.Remediation) string {
+	if remediation == nil {
+		return ""
 	}
+	return strings.Join(remediation.Path, " -> ")
 }
 
 func CombineReports(reports []*types.InventoryReport) types.InventoryReport {
@@ -54,18 +76,69 @@ func CombineReports(reports []*types.InventoryReport) types.InventoryReport {
 			continue
 		}
 		summary.Resources = append(summary.Resources, report.Resources...)
+		summary.Errors = append(summary.Errors, report.Errors...)
+		summary.Stats = append(summary.Stats, report.Stats...)
 	}
 	return summary
 }
 
+// CommonIdentity keeps reports of a single account attributed to it
+func CommonIdentity(reports []*types.InventoryReport) types.Indentity {
+	identity := types.Indentity{}
+	for i, report := range reports {
+		if i > 0 && report.Identity != identity {
+			return types.Indentity{}
+		}
+		identity = report.Identity
+	}
+	return identity
+}
+
+// FormatScrapeError renders a scrape error on a single line, e.g. "aws/lambda/123/us-east-1: access denied"
+func FormatScrapeError(e types.ScrapeError) string {
+	scope := []string{}
+	for _, field := range []string{e.Source, e.Extractor, e.Account, e.Region} {
+		if len(field) > 0 {
+			scope = append(scope, field)
+		}
+	}
+	if e.Resource != nil {
+		scope = append(scope, string(e.Resource.Kind)+":"+e.Resource.ID)
+	}
+	return strings.Join(scope, "/") + ": " + strings.Join(strings.Fields(e.Message), " ")
+}
+
+func ErrorsToTable(report types.InventoryReport) [][]string {
+	var table [][]string
+	for _, e := range report.Errors {
+		resource := ""
+		if e.Resource != nil {
+			resource = string(e.Resource.Kind) + ":" + e.Resource.ID
+		}
+		table = append(table, []string{
+			e.Source,
+			e.Extractor,
+			e.Account,
+			e.Region,
+			resource,
+			e.Message,
+		})
+	}
+	return table
+}
+
 func FilterReport(report *types.InventoryReport, filter ReportFilter) *types.InventoryReport {
 	if report == nil {
 		return nil
 	}
-	filtered := types.InventoryReport{}
+	filtered := types.InventoryReport{
+		Identity: report.Identity,
+		Errors:   report.Errors,
+		Stats:    report.Stats,
+	}
 
 	for _, item := range report.Resources {
-		if isMatch(item.GetVersionedResource(), filter) {
+		if IsMatch(item.GetVersionedResource(), filter) {
 			filtered.Resources = append(filtered.Resources, item)
 		}
 	}
@@ -73,7 +146,8 @@ func FilterReport(report *types.InventoryReport, filter ReportFilter) *types.Inv
 	return &filtered
 }
 
-func isMatch(item types.VersionedResource, filter ReportFilter) bool {
+// IsMatch tells whether a resource passes the filter
+func IsMatch(item types.VersionedResource, filter ReportFilter) bool {
 	if len(filter.ResourceKinds) > 0 {
 		found := false
 		for _, kind := range filter.ResourceKinds {
@@ -142,7 +216,53 @@ func isMatch(item types.VersionedResource, filter ReportFilter) bool {
 	}
 
 	if len(filter.Version) > 0 {
-		if item.Version != filter.Version {
+		// Semver-aware, like version = in expressions, e.g. 1.27 matches 1.27.0
+		if cmp, ok := compareVersions(item.Version, filter.Version); !ok || cmp != 0 {
+			return false
+		}
+	}
+
+	if len(filter.Owners) > 0 {
+		found := false
+		for _, owner := range filter.Owners {
+			if item.Owner == owner {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if filter.Managed != nil {
+		if *filter.Managed && len(item.GitOpsReference.Workspace) == 0 {
+			return false
+		}
+		if !*filter.Managed && !item.Unmanaged {
+			return false
+		}
+	}
+
+	for _, expr := range filter.Expressions {
+		if !expr.Match(item) {
+			return false
+		}
+	}
+
+	for key, values := range filter.Labels {
+		value, ok := item.Labels[key]
+		if !ok {
+			return false
+		}
+		found := false
+		for _, v := range values {
+			if value == v {
+				found = true
+				break
+			}
+		}
+		if !found {
 			return false
 		}
 	}
@@ -165,32 +285,115 @@ func RemainingDays(eolDate time.Time) int {
 	return int(diff.Hours() / 24)
 }
 
-func CreateFilter(f []string) ReportFilter {
+// legacyFilterKeys are the keys of the original `key=value` filters, repeating a key matches any of its values
+var legacyFilterKeys = map[string]bool{
+	"status": true, "version": true, "id": true, "kind": true, "parent.kind": true, "parent.id": true, "owner": true, "managed": true,
+}
+
+// expressionKeywords tell expressions apart from `key=value` pairs with spaces in their value, like
+// tag.Name=my app
+var expressionKeywords = map[string]bool{"and": true, "or": true, "not": true, "in": true}
+
+func isLegacyKey(key string) bool {
+	if tag, ok := strings.CutPrefix(key, "tag."); ok {
+		return len(tag) > 0 && !strings.ContainsAny(tag, " \t")
+	}
+	return legacyFilterKeys[key]
+}
+
+// isLegacyFilter tells `key=value` filters apart from expressions, which are parsed by ParseFilterExpression
+func isLegacyFilter(kv string) bool {
+	key, value, ok := strings.Cut(kv, "=")
+	if !ok || strings.ContainsAny(value, "()\"=!<>~") {
+		return false
+	}
+	for _, word := range strings.Fields(strings.ToLower(value)) {
+		if expressionKeywords[word] {
+			return false
+		}
+	}
+	return isLegacyKey(key)
+}
+
+// splitLegacyFilters splits comma separated `key=value` pairs, e.g. kind=eks,kind=rds, as -f used to.
+// A comma which does not start a new pair belongs to the value, e.g. status=warning,critical. Filters
+// which are not all pairs are expressions.
+func splitLegacyFilters(kv string) ([]string, bool) {
+	pairs := []string{}
+	for _, part := range strings.Split(kv, ",") {
+		key, _, ok := strings.Cut(part, "=")
+		if len(pairs) == 0 || (ok && isLegacyKey(key)) {
+			pairs = append(pairs, part)
+			continue
+		}
+		pairs[len(pairs)-1] += "," + part
+	}
+	for _, pair := range pairs {
+		if !isLegacyFilter(pair) {
+			return nil, false
+		}
+	}
+	return pairs, true
+}
+
+// CreateFilter builds a filter from `key=value` pairs and filter expressions, all of which must match
+func CreateFilter(f []string) (ReportFilter, error) {
 	filter := ReportFilter{}
 
-	for _, kv := range f {
-		parts := strings.Split(kv, "=")
-		if len(parts) != 2 {
+	for _, arg := range f {
+		pairs, ok := splitLegacyFilters(arg)
+		if !ok {
+			expr, err := ParseFilterExpression(arg)
+			if err != nil {
+				return ReportFilter{}, fmt.Errorf("invalid filter %q: %w", arg, err)
+			}
+			filter.Expressions = append(filter.Expressions, expr)
 			continue
 		}
-		switch parts[0] {
-		case "status":
-			statuses := strings.Split(strings.ToUpper(parts[1]), ",")
-			for _, status := range statuses {
-				filter.Status = append(filter.Status, types.Status(status))
+
+		for _, kv := range pairs {
+			err := filter.addPair(kv)
+			if err != nil {
+				return ReportFilter{}, err
 			}
-		case "version":
-			filter.Version = parts[1]
-		case "id":
-			filter.IDs = append(filter.IDs, parts[1])
-		case "kind":
-			filter.ResourceKinds = append(filter.ResourceKinds, types.ResourceKind(parts[1]))
-		case "parent.kind":
-			filter.ParentKinds = append(filter.ParentKinds, types.ResourceKind(parts[1]))
-		case "parent.id":
-			filter.ParentIDs = append(filter.ParentIDs, parts[1])
 		}
 	}
 
-	return filter
+	return filter, nil
+}
+
+func (filter *ReportFilter) addPair(kv string) error {
+	key, value, _ := strings.Cut(kv, "=")
+	switch key {
+	case "status":
+		statuses := strings.Split(strings.ToUpper(value), ",")
+		for _, status := range statuses {
+			filter.Status = append(filter.Status, types.Status(status))
+		}
+	case "version":
+		filter.Version = value
+	case "id":
+		filter.IDs = append(filter.IDs, value)
+	case "kind":
+		filter.ResourceKinds = append(filter.ResourceKinds, types.ResourceKind(value))
+	case "parent.kind":
+		filter.ParentKinds = append(filter.ParentKinds, types.ResourceKind(value))
+	case "parent.id":
+		filter.ParentIDs = append(filter.ParentIDs, value)
+	case "owner":
+		filter.Owners = append(filter.Owners, value)
+	case "managed":
+		managed, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid filter %q: managed is true or false", kv)
+		}
+		filter.Managed = &managed
+	default:
+		tag, _ := strings.CutPrefix(key, "tag.")
+		if filter.Labels == nil {
+			filter.Labels = map[string][]string{}
+		}
+		filter.Labels[tag] = append(filter.Labels[tag], value)
+	}
+	return nil
 }
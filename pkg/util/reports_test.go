@@ -9,22 +9,26 @@ import (
 
 func TestCreateFilter(t *testing.T) {
 	r := require.New(t)
-	f := CreateFilter([]string{"kind=" + string(types.KindEKSCluster)})
+	f, err := CreateFilter([]string{"kind=" + string(types.KindEKSCluster)})
+	r.NoError(err)
 	r.True(len(f.ResourceKinds) == 1)
 	r.True(f.ResourceKinds[0] == types.KindEKSCluster)
 
-	f = CreateFilter([]string{"parent.kind=" + string(types.KindEKSCluster)})
+	f, err = CreateFilter([]string{"parent.kind=" + string(types.KindEKSCluster)})
+	r.NoError(err)
 	r.True(len(f.ParentKinds) == 1)
 	r.True(f.ParentKinds[0] == types.KindEKSCluster)
 
-	f = CreateFilter([]string{"parent.kind=" + string(types.KindAWSAccount), "parent.id=123456789012"})
+	f, err = CreateFilter([]string{"parent.kind=" + string(types.KindAWSAccount), "parent.id=123456789012"})
+	r.NoError(err)
 	r.True(len(f.ParentKinds) == 1)
 	r.True(len(f.ParentIDs) == 1)
 	r.True(len(f.IDs) == 0)
 	r.True(f.ParentKinds[0] == types.KindAWSAccount)
 	r.True(f.ParentIDs[0] == "123456789012")
 
-	f = CreateFilter([]string{"parent.kind=" + string(types.KindAWSAccount), "parent.id=123456789012", "id=abc"})
+	f, err = CreateFilter([]string{"parent.kind=" + string(types.KindAWSAccount), "parent.id=123456789012", "id=abc"})
+	r.NoError(err)
 	r.True(len(f.ParentKinds) == 1)
 	r.True(len(f.ParentIDs) == 1)
 	r.True(len(f.IDs) == 1)
@@ -32,8 +36,109 @@ func TestCreateFilter(t *testing.T) {
 	r.True(f.ParentIDs[0] == "123456789012")
 	r.True(f.IDs[0] == "abc")
 
-	f = CreateFilter([]string{"status=valid,warning"})
+	f, err = CreateFilter([]string{"status=valid,warning"})
+	r.NoError(err)
 	r.True(len(f.Status) == 2)
 	r.True(f.Status[0] == types.StatusValid)
 	r.True(f.Status[1] == types.StatusWarning)
 }
+
+func TestCreateFilterLegacyPairs(t *testing.T) {
+	r := require.New(t)
+
+	// Comma separated pairs are split like -f used to, other commas belong to the value
+	f, err := CreateFilter([]string{"kind=eks,kind=rds"})
+	r.NoError(err)
+	r.Equal([]types.ResourceKind{types.KindEKSCluster, types.KindRDSCluster}, f.ResourceKinds)
+	r.Empty(f.Expressions)
+
+	f, err = CreateFilter([]string{"status=warning,critical,kind=eks,tag.team=a,b"})
+	r.NoError(err)
+	r.Equal([]types.Status{types.StatusWarning, types.StatusCritical}, f.Status)
+	r.Equal([]types.ResourceKind{types.KindEKSCluster}, f.ResourceKinds)
+	r.Equal(map[string][]string{"team": {"a,b"}}, f.Labels)
+
+	f, err = CreateFilter([]string{"tag.Name=my app", "owner=data platform"})
+	r.NoError(err)
+	r.Equal(map[string][]string{"Name": {"my app"}}, f.Labels)
+	r.Equal([]string{"data platform"}, f.Owners)
+	r.Empty(f.Expressions)
+
+	for _, expr := range []string{"kind=eks and status=critical", "owner=data and not managed = true", "kind in (eks,rds)", "kind = eks"} {
+		f, err = CreateFilter([]string{expr})
+		r.NoError(err, expr)
+		r.Len(f.Expressions, 1, expr)
+	}
+
+	// version= compares versions like the expressions do
+	report := expressionReport()
+	for _, filters := range [][]string{{"version=1.24.0"}, {"version = 1.24.0"}, {"version=1.24"}, {"version = 1.24"}} {
+		f, err = CreateFilter(filters)
+		r.NoError(err)
+		filtered := FilterReport(report, f)
+		r.Len(filtered.Resources, 1, filters)
+		r.Equal("prod", filtered.Resources[0].GetVersionedResource().ID, filters)
+	}
+}
+
+func TestCombineAndFilterReportErrors(t *testing.T) {
+	r := require.New(t)
+
+	reports := []*types.InventoryReport{
+		{
+			Resources: []types.Versioned{
+				types.EKSCluster{VersionedResource: types.VersionedResource{Kind: types.KindEKSCluster, ID: "cluster1"}},
+			},
+		},
+		nil,
+		{
+			Errors: []types.ScrapeError{{
+				Source:   "aws",
+				Region:   "us-west-2",
+				Resource: &types.ParentResource{Kind: types.KindEKSCluster, ID: "cluster2"},
+				Message:  "unable to describe cluster",
+			}},
+		},
+	}
+
+	report := CombineReports(reports)
+	r.Len(report.Resources, 1)
+	r.Len(report.Errors, 1)
+	r.False(report.Complete())
+
+	report.Identity.AwsAccountNumber = "123456789012"
+	f, err := CreateFilter([]string{"kind=rds"})
+	r.NoError(err)
+	filtered := FilterReport(&report, f)
+	r.Empty(filtered.Resources)
+	r.Len(filtered.Errors, 1)
+	r.Equal("123456789012", filtered.Identity.AwsAccountNumber)
+
+	table := ErrorsToTable(*filtered)
+	r.Equal([][]string{{"aws", "", "", "us-west-2", "eks:cluster2", "unable to describe cluster"}}, table)
+
+	r.True(CombineReports(reports[:1]).Complete())
+}
+
+func TestLabelFilter(t *testing.T) {
+	r := require.New(t)
+
+	f, err := CreateFilter([]string{"tag.team=payments", "tag.team=billing", "tag.env=prod"})
+	r.NoError(err)
+	r.Equal(map[string][]string{"team": {"payments", "billing"}, "env": {"prod"}}, f.Labels)
+
+	report := &types.InventoryReport{
+		Resources: []types.Versioned{
+			types.Lambda{VersionedResource: types.VersionedResource{Kind: types.KindLambda, ID: "fn1", Labels: map[string]string{"team": "payments", "env": "prod"}}},
+			types.Lambda{VersionedResource: types.VersionedResource{Kind: types.KindLambda, ID: "fn2", Labels: map[string]string{"team": "billing", "env": "dev"}}},
+			types.Lambda{VersionedResource: types.VersionedResource{Kind: types.KindLambda, ID: "fn3"}},
+		},
+	}
+	filtered := FilterReport(report, f)
+	r.Len(filtered.Resources, 1)
+	r.Equal("fn1", filtered.Resources[0].GetVersionedResource().ID)
+
+	table := ReportToTable(*report, "team")
+	r.Equal("payments", table[0][len(table[0])-1])
+	r.Equal("", table[2][len(table[2])-1])
+}
@@ -0,0 +1,199 @@
+package util
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+)
+
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+// SarifLog is a SARIF 2.1.0 log, as uploaded to GitHub code scanning. Only the properties camelot fills
+// are modelled.
+type SarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []SarifRun `json:"runs"`
+}
+
+type SarifRun struct {
+	Tool        SarifTool         `json:"tool"`
+	Invocations []SarifInvocation `json:"invocations,omitempty"`
+	Results     []SarifResult     `json:"results"`
+}
+
+// SarifInvocation tells whether the scrape was complete, with its errors as notifications
+type SarifInvocation struct {
+	ExecutionSuccessful        bool                `json:"executionSuccessful"`
+	ToolExecutionNotifications []SarifNotification `json:"toolExecutionNotifications,omitempty"`
+}
+
+type SarifNotification struct {
+	Level   string       `json:"level"`
+	Message SarifMessage `json:"message"`
+}
+
+type SarifTool struct {
+	Driver SarifDriver `json:"driver"`
+}
+
+type SarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []SarifRule `json:"rules"`
+}
+
+type SarifRule struct {
+	ID               string       `json:"id"`
+	Name             string       `json:"name"`
+	ShortDescription SarifMessage `json:"shortDescription"`
+	HelpURI          string       `json:"helpUri,omitempty"`
+}
+
+type SarifMessage struct {
+	Text string `json:"text"`
+}
+
+type SarifResult struct {
+	RuleID     string            `json:"ruleId"`
+	Level      string            `json:"level"`
+	Message    SarifMessage      `json:"message"`
+	Locations  []SarifLocation   `json:"locations"`
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+type SarifLocation struct {
+	PhysicalLocation SarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type SarifPhysicalLocation struct {
+	ArtifactLocation SarifArtifactLocation `json:"artifactLocation"`
+	Region           SarifRegion           `json:"region"`
+}
+
+type SarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type SarifRegion struct {
+	StartLine int `json:"startLine"`
+	EndLine   int `json:"endLine,omitempty"`
+}
+
+// sarifRules describes the findings by kind, other kinds declared in code share the end-of-life rule
+var sarifRules = map[types.ResourceKind]SarifRule{
+	types.KindTFCProvider: {
+		ID:               "camelot/outdated-provider",
+		Name:             "OutdatedProviderConstraint",
+		ShortDescription: SarifMessage{Text: "The version constraint of a Terraform provider excludes or trails its current release"},
+	},
+	types.KindTerrfaormModule: {
+		ID:               "camelot/stale-module-ref",
+		Name:             "StaleModuleRef",
+		ShortDescription: SarifMessage{Text: "A Terraform module is pinned to a ref older than its latest release"},
+	},
+}
+
+var sarifEOLRule = SarifRule{
+	ID:               "camelot/end-of-life",
+	Name:             "EndOfLife",
+	ShortDescription: SarifMessage{Text: "A resource is past or nearing its end of life"},
+}
+
+var sarifLevels = map[types.Status]string{
+	types.StatusCritical: "error",
+	types.StatusWarning:  "warning",
+}
+
+// ReportToSarif turns the WARNING and CRITICAL resources declared in code (with a GitOps file) into
+// SARIF results, located at the lines of their declaration. Locations are relative to the repo root, so
+// a report spanning several repos should be filtered by gitops.repo before it is uploaded.
+func ReportToSarif(report types.InventoryReport) SarifLog {
+	results := []SarifResult{}
+	rules := map[string]SarifRule{}
+	for _, item := range report.Resources {
+		resource := item.GetVersionedResource()
+		level, ok := sarifLevels[resource.EOL.Status]
+		if !ok || len(resource.GitOpsReference.File) == 0 {
+			continue
+		}
+
+		rule, ok := sarifRules[resource.Kind]
+		if !ok {
+			rule = sarifEOLRule
+		}
+		rules[rule.ID] = rule
+
+		region := SarifRegion{StartLine: resource.GitOpsReference.StartLine, EndLine: resource.GitOpsReference.EndLine}
+		if region.StartLine == 0 {
+			region = SarifRegion{StartLine: 1}
+		}
+		result := SarifResult{
+			RuleID:  rule.ID,
+			Level:   level,
+			Message: SarifMessage{Text: sarifMessage(resource)},
+			Locations: []SarifLocation{{PhysicalLocation: SarifPhysicalLocation{
+				ArtifactLocation: SarifArtifactLocation{URI: resource.GitOpsReference.File},
+				Region:           region,
+			}}},
+			Properties: map[string]string{
+				"kind":   string(resource.Kind),
+				"id":     resource.ID,
+				"status": string(resource.EOL.Status),
+			},
+		}
+		if len(resource.GitOpsReference.Repo) > 0 {
+			result.Properties["repo"] = resource.GitOpsReference.Repo
+		}
+		results = append(results, result)
+	}
+
+	driver := SarifDriver{Name: "camelot", InformationURI: "https://github.com/chanzuckerberg/camelot", Rules: []SarifRule{}}
+	for _, rule := range rules {
+		driver.Rules = append(driver.Rules, rule)
+	}
+	sort.Slice(driver.Rules, func(i, j int) bool {
+		return driver.Rules[i].ID < driver.Rules[j].ID
+	})
+
+	// Scrape errors make the run unsuccessful, as resources may be missing from its results
+	invocation := SarifInvocation{ExecutionSuccessful: len(report.Errors) == 0}
+	for _, e := range report.Errors {
+		invocation.ToolExecutionNotifications = append(invocation.ToolExecutionNotifications, SarifNotification{
+			Level:   "error",
+			Message: SarifMessage{Text: FormatScrapeError(e)},
+		})
+	}
+
+	return SarifLog{
+		Schema:  sarifSchema,
+		Version: "2.1.0",
+		Runs:    []SarifRun{{Tool: SarifTool{Driver: driver}, Invocations: []SarifInvocation{invocation}, Results: results}},
+	}
+}
+
+// sarifMessage reads e.g. "hashicorp/aws ~> 2.0 is CRITICAL: the current version is 5.40.0"
+func sarifMessage(resource types.VersionedResource) string {
+	message := resource.ID
+	if len(resource.Version) > 0 {
+		message += " " + resource.Version
+	}
+	message += " is " + string(resource.EOL.Status)
+
+	details := []string{}
+	if len(resource.CurrentVersion) > 0 && resource.CurrentVersion != resource.Version {
+		details = append(details, "the current version is "+resource.CurrentVersion)
+	}
+	if len(resource.EOL.EOLDate) > 0 && resource.Kind != types.KindTFCProvider && resource.Kind != types.KindTerrfaormModule {
+		details = append(details, "end of life "+resource.EOL.EOLDate)
+	}
+	if path := FormatUpgradePath(resource.Remediation); len(path) > 0 {
+		details = append(details, fmt.Sprintf("upgrade through %s", path))
+	}
+	if len(details) > 0 {
+		message += ": " + strings.Join(details, ", ")
+	}
+	return message
+}
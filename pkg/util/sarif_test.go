@@ -0,0 +1,55 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportToSarif(t *testing.T) {
+	r := require.New(t)
+	report := types.InventoryReport{Resources: []types.Versioned{
+		types.TfcProvider{VersionedResource: types.VersionedResource{Kind: types.KindTFCProvider, ID: "hashicorp/aws", Version: "~> 2.0", CurrentVersion: "5.40.0",
+			GitOpsReference: types.GitOpsReference{Repo: "infra", File: "stacks/versions.tf", StartLine: 3, EndLine: 6},
+			EOL:             types.EOLStatus{EOLDate: "2024-03-07", Status: types.StatusCritical}}},
+		types.TerraformModule{VersionedResource: types.VersionedResource{Kind: types.KindTerrfaormModule, ID: "cztack//aws-vpc", Version: "v0.40.0", CurrentVersion: "v0.60.0",
+			GitOpsReference: types.GitOpsReference{Repo: "infra", File: "envs/prod/main.tf", StartLine: 6, EndLine: 6},
+			EOL:             types.EOLStatus{Status: types.StatusWarning}}},
+		// Up to date, or not declared in code
+		types.TerraformModule{VersionedResource: types.VersionedResource{Kind: types.KindTerrfaormModule, ID: "cztack//aws-vpc", Version: "v0.60.0",
+			GitOpsReference: types.GitOpsReference{Repo: "infra", File: "envs/dev/main.tf", StartLine: 2, EndLine: 2},
+			EOL:             types.EOLStatus{Status: types.StatusValid}}},
+		types.EKSCluster{VersionedResource: types.VersionedResource{Kind: types.KindEKSCluster, ID: "prod", Version: "1.24",
+			EOL: types.EOLStatus{Status: types.StatusCritical}}},
+	}}
+
+	log := ReportToSarif(report)
+	r.Equal("2.1.0", log.Version)
+	r.Len(log.Runs, 1)
+	run := log.Runs[0]
+	r.Equal([]string{"camelot/outdated-provider", "camelot/stale-module-ref"},
+		[]string{run.Tool.Driver.Rules[0].ID, run.Tool.Driver.Rules[1].ID})
+
+	r.Len(run.Results, 2)
+	r.Equal("camelot/outdated-provider", run.Results[0].RuleID)
+	r.Equal("error", run.Results[0].Level)
+	r.Equal("hashicorp/aws ~> 2.0 is CRITICAL: the current version is 5.40.0", run.Results[0].Message.Text)
+	r.Equal(SarifPhysicalLocation{
+		ArtifactLocation: SarifArtifactLocation{URI: "stacks/versions.tf"},
+		Region:           SarifRegion{StartLine: 3, EndLine: 6},
+	}, run.Results[0].Locations[0].PhysicalLocation)
+	r.Equal("infra", run.Results[0].Properties["repo"])
+
+	r.Equal("warning", run.Results[1].Level)
+	r.Equal("envs/prod/main.tf", run.Results[1].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	r.Equal([]SarifInvocation{{ExecutionSuccessful: true}}, run.Invocations)
+
+	// Scrape errors make the run unsuccessful
+	report.Errors = []types.ScrapeError{{Source: "github", Extractor: "modules", Message: "rate\nlimited"}}
+	run = ReportToSarif(report).Runs[0]
+	r.Len(run.Invocations, 1)
+	r.False(run.Invocations[0].ExecutionSuccessful)
+	r.Equal([]SarifNotification{{Level: "error", Message: SarifMessage{Text: "github/modules: rate limited"}}},
+		run.Invocations[0].ToolExecutionNotifications)
+}
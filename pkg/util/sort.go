@@ -0,0 +1,96 @@
+package util
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+)
+
+type sortKey func(a, b types.VersionedResource) int
+
+var sortFields = map[string]sortKey{
+	"kind": func(a, b types.VersionedResource) int { return strings.Compare(string(a.Kind), string(b.Kind)) },
+	"id":   func(a, b types.VersionedResource) int { return strings.Compare(a.ID, b.ID) },
+	"parent": func(a, b types.VersionedResource) int {
+		return strings.Compare(FormatParents(a.Parents), FormatParents(b.Parents))
+	},
+	"account": func(a, b types.VersionedResource) int { return strings.Compare(AccountOf(a), AccountOf(b)) },
+	"owner":   func(a, b types.VersionedResource) int { return strings.Compare(a.Owner, b.Owner) },
+	"version": compareSortVersions,
+	// status sorts the most severe first
+	"status": func(a, b types.VersionedResource) int {
+		return statusSeverity(b.EOL.Status) - statusSeverity(a.EOL.Status)
+	},
+	"eol.date":           func(a, b types.VersionedResource) int { return strings.Compare(a.EOL.EOLDate, b.EOL.EOLDate) },
+	"eol.remaining_days": func(a, b types.VersionedResource) int { return a.EOL.RemainingDays - b.EOL.RemainingDays },
+}
+
+// compareSortVersions orders versions by prefix then number (python3.8 before python3.12), followed by
+// the versions which cannot be parsed, by name, so that the order is the same whatever the input order
+func compareSortVersions(a, b types.VersionedResource) int {
+	prefixA, versionA, okA := parseVersion(a.Version)
+	prefixB, versionB, okB := parseVersion(b.Version)
+	switch {
+	case okA && !okB:
+		return -1
+	case !okA && okB:
+		return 1
+	case !okA && !okB:
+		return strings.Compare(a.Version, b.Version)
+	}
+	if prefixA != prefixB {
+		return strings.Compare(prefixA, prefixB)
+	}
+	if cmp := versionA.Compare(versionB); cmp != 0 {
+		return cmp
+	}
+	return strings.Compare(a.Version, b.Version)
+}
+
+func init() {
+	sortFields["name"] = sortFields["id"]
+}
+
+// SortFields lists the fields reports can be sorted by
+func SortFields() []string {
+	fields := []string{}
+	for field := range sortFields {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// SortReport sorts the resources of a report in place by the given fields, each of which can be
+// prefixed with - to sort in descending order. Resources equal in every field keep their order.
+func SortReport(report *types.InventoryReport, fields []string) error {
+	keys := []sortKey{}
+	for _, field := range fields {
+		name, descending := strings.CutPrefix(field, "-")
+		key, ok := sortFields[name]
+		if !ok {
+			return fmt.Errorf("unable to sort by %q, supported fields are: %s", field, strings.Join(SortFields(), ", "))
+		}
+		if descending {
+			ascending := key
+			key = func(a, b types.VersionedResource) int { return ascending(b, a) }
+		}
+		keys = append(keys, key)
+	}
+	if report == nil || len(keys) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(report.Resources, func(i, j int) bool {
+		a, b := report.Resources[i].GetVersionedResource(), report.Resources[j].GetVersionedResource()
+		for _, key := range keys {
+			if cmp := key(a, b); cmp != 0 {
+				return cmp < 0
+			}
+		}
+		return false
+	})
+	return nil
+}
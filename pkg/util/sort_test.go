@@ -0,0 +1,60 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSortReport(t *testing.T) {
+	r := require.New(t)
+	report := expressionReport()
+	ids := func() []string {
+		ids := []string{}
+		for _, item := range report.Resources {
+			ids = append(ids, item.GetVersionedResource().ID)
+		}
+		return ids
+	}
+
+	r.NoError(SortReport(report, []string{"eol.remaining_days"}))
+	r.Equal([]string{"sandbox-1", "prod", "api", "analytics"}, ids())
+	r.NoError(SortReport(report, []string{"-eol.remaining_days"}))
+	r.Equal([]string{"analytics", "api", "prod", "sandbox-1"}, ids())
+	r.NoError(SortReport(report, []string{"status", "-id"}))
+	r.Equal([]string{"sandbox-1", "prod", "api", "analytics"}, ids())
+	r.NoError(SortReport(report, []string{"kind", "version"}))
+	r.Equal([]string{"sandbox-1", "prod", "api", "analytics"}, ids())
+	r.Error(SortReport(report, []string{"size"}))
+}
+
+func TestSortReportVersions(t *testing.T) {
+	r := require.New(t)
+	report := &types.InventoryReport{}
+	for _, v := range []string{"unversioned", "python3.12", "1.10", "nodejs20.x", "latest", "1.9", "python3.8", "1.27.1", "nodejs18.x", "v1.9.1"} {
+		report.Resources = append(report.Resources, types.Lambda{VersionedResource: types.VersionedResource{Kind: types.KindLambda, ID: v, Version: v}})
+	}
+	versions := func() []string {
+		versions := []string{}
+		for _, item := range report.Resources {
+			versions = append(versions, item.GetVersionedResource().Version)
+		}
+		return versions
+	}
+
+	// Comparable versions by prefix and number, then the others
+	expected := []string{"1.9", "v1.9.1", "1.10", "1.27.1", "nodejs18.x", "nodejs20.x", "python3.8", "python3.12", "latest", "unversioned"}
+	r.NoError(SortReport(report, []string{"version"}))
+	r.Equal(expected, versions())
+
+	// The order does not depend on the order of the resources
+	for i, j := 0, len(report.Resources)-1; i < j; i, j = i+1, j-1 {
+		report.Resources[i], report.Resources[j] = report.Resources[j], report.Resources[i]
+	}
+	r.NoError(SortReport(report, []string{"version"}))
+	r.Equal(expected, versions())
+
+	r.NoError(SortReport(report, []string{"-version"}))
+	r.Equal([]string{"unversioned", "latest", "python3.12", "python3.8", "nodejs20.x", "nodejs18.x", "1.27.1", "1.10", "v1.9.1", "1.9"}, versions())
+}
@@ -0,0 +1,116 @@
+package util
+
+import (
+	"sort"
+	"time"
+
+	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+)
+
+// DefaultSoonest is the number of soonest expiring resources of a summary
+const DefaultSoonest = 10
+
+// ReportSummary rolls a report up into status counts, by kind and by scope (AWS account, GitHub or TFC
+// org), with the resources past or closest to their end of life
+type ReportSummary struct {
+	Resources int                                   `json:"resources"`
+	Errors    int                                   `json:"errors"`
+	Statuses  map[string]int                        `json:"statuses"`
+	Kinds     map[types.ResourceKind]map[string]int `json:"kinds"`
+	Scopes    map[string]map[string]int             `json:"scopes"`
+	Soonest   []types.VersionedResource             `json:"soonest_eol"`
+}
+
+// scopeKinds are the parents a resource is counted under, in order of precedence
+var scopeKinds = []types.ResourceKind{types.KindAWSAccount, types.KindGithubOrg, types.KindTFCOrg, types.KindGithubRepo}
+
+// ScopeOf returns the AWS account, GitHub org, TFC org or else GitHub repo of a resource as kind:id, or
+// the account of the report
+func ScopeOf(report types.InventoryReport, item types.VersionedResource) string {
+	for _, kind := range scopeKinds {
+		for _, p := range item.Parents {
+			if p.Kind == kind {
+				return FormatParents([]types.ParentResource{p})
+			}
+		}
+	}
+	if len(report.Identity.AwsAccountNumber) > 0 {
+		return FormatParents([]types.ParentResource{{Kind: types.KindAWSAccount, ID: report.Identity.AwsAccountNumber}})
+	}
+	return NoValue
+}
+
+// UpcomingEOL lists the resources with an end of life date after now, soonest first
+func UpcomingEOL(report types.InventoryReport, now time.Time) []types.VersionedResource {
+	today := now.Format("2006-01-02")
+	upcoming := []types.VersionedResource{}
+	for _, item := range report.Resources {
+		resource := item.GetVersionedResource()
+		if _, err := time.Parse("2006-01-02", resource.EOL.EOLDate); err != nil || resource.EOL.EOLDate < today {
+			continue
+		}
+		upcoming = append(upcoming, resource)
+	}
+	sort.SliceStable(upcoming, func(i, j int) bool {
+		return upcoming[i].EOL.EOLDate < upcoming[j].EOL.EOLDate
+	})
+	return upcoming
+}
+
+// PastEOL lists the resources which reached their end of life before now: those without a date first,
+// then the others by date, the longest expired first
+func PastEOL(report types.InventoryReport, now time.Time) []types.VersionedResource {
+	today := now.Format("2006-01-02")
+	past := []types.VersionedResource{}
+	for _, item := range report.Resources {
+		resource := item.GetVersionedResource()
+		_, err := time.Parse("2006-01-02", resource.EOL.EOLDate)
+		if resource.EOL.EOLDate == "true" || (err == nil && resource.EOL.EOLDate < today) {
+			past = append(past, resource)
+		}
+	}
+	sort.SliceStable(past, func(i, j int) bool {
+		if past[i].EOL.EOLDate == "true" || past[j].EOL.EOLDate == "true" {
+			return past[i].EOL.EOLDate == "true" && past[j].EOL.EOLDate != "true"
+		}
+		return past[i].EOL.EOLDate < past[j].EOL.EOLDate
+	})
+	return past
+}
+
+// SummarizeReport counts the resources of a report by status, kind and scope, and picks the most urgent
+// resources: those past their end of life first, then those reaching it the soonest
+func SummarizeReport(report types.InventoryReport, now time.Time, soonest int) ReportSummary {
+	summary := ReportSummary{
+		Resources: len(report.Resources),
+		Errors:    len(report.Errors),
+		Statuses:  map[string]int{},
+		Kinds:     map[types.ResourceKind]map[string]int{},
+		Scopes:    map[string]map[string]int{},
+		Soonest:   []types.VersionedResource{},
+	}
+	for _, item := range report.Resources {
+		resource := item.GetVersionedResource()
+		status := string(resource.EOL.Status)
+		if len(status) == 0 {
+			status = NoValue
+		}
+		summary.Statuses[status]++
+		if summary.Kinds[resource.Kind] == nil {
+			summary.Kinds[resource.Kind] = map[string]int{}
+		}
+		summary.Kinds[resource.Kind][status]++
+		scope := ScopeOf(report, resource)
+		if summary.Scopes[scope] == nil {
+			summary.Scopes[scope] = map[string]int{}
+		}
+		summary.Scopes[scope][status]++
+	}
+
+	urgent := append(PastEOL(report, now), UpcomingEOL(report, now)...)
+	if soonest >= 0 && len(urgent) > soonest {
+		urgent = urgent[:soonest]
+	}
+	summary.Soonest = append(summary.Soonest, urgent...)
+	return summary
+}
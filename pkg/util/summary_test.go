@@ -0,0 +1,67 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chanzuckerberg/camelot/pkg/scraper/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummarizeReport(t *testing.T) {
+	r := require.New(t)
+	report := expressionReport()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	dates := map[string]string{"prod": "2024-01-21", "sandbox-1": "2024-01-11", "analytics": "2023-06-01"}
+	for i, item := range report.Resources {
+		resource := item.GetVersionedResource()
+		resource.EOL.EOLDate = dates[resource.ID]
+		report.Resources[i] = types.Lambda{VersionedResource: resource}
+	}
+	report.Errors = []types.ScrapeError{{Source: "aws", Message: "access denied"}}
+
+	summary := SummarizeReport(*report, now, 1)
+	r.Equal(4, summary.Resources)
+	r.Equal(1, summary.Errors)
+	r.Equal(map[string]int{"CRITICAL": 2, "VALID": 1, "WARNING": 1}, summary.Statuses)
+	r.Equal(map[string]int{"CRITICAL": 2}, summary.Kinds[types.KindEKSCluster])
+	r.Equal(map[string]int{"CRITICAL": 1, "VALID": 1, "WARNING": 1}, summary.Scopes["aws:prod-account"])
+	r.Equal(map[string]int{"CRITICAL": 1}, summary.Scopes["aws:sandbox-account"])
+
+	// Resources past their end of life come first, missing end of life dates are left out
+	r.Len(summary.Soonest, 1)
+	r.Equal("analytics", summary.Soonest[0].ID)
+	r.Len(UpcomingEOL(*report, now), 2)
+	soonest := []string{}
+	for _, resource := range SummarizeReport(*report, now, 10).Soonest {
+		soonest = append(soonest, resource.ID)
+	}
+	r.Equal([]string{"analytics", "sandbox-1", "prod"}, soonest)
+}
+
+func TestPastEOL(t *testing.T) {
+	r := require.New(t)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	report := types.InventoryReport{}
+	for id, date := range map[string]string{"old": "2022-05-01", "recent": "2023-12-31", "undated": "true", "today": "2024-01-01", "none": ""} {
+		report.Resources = append(report.Resources, types.Lambda{VersionedResource: types.VersionedResource{Kind: types.KindLambda, ID: id, EOL: types.EOLStatus{EOLDate: date}}})
+	}
+
+	past := []string{}
+	for _, resource := range PastEOL(report, now) {
+		past = append(past, resource.ID)
+	}
+	r.Equal([]string{"undated", "old", "recent"}, past)
+}
+
+func TestScopeOf(t *testing.T) {
+	r := require.New(t)
+
+	report := types.InventoryReport{Identity: types.Indentity{AwsAccountNumber: "123"}}
+	r.Equal("aws:123", ScopeOf(report, types.VersionedResource{}))
+	r.Equal(NoValue, ScopeOf(types.InventoryReport{}, types.VersionedResource{}))
+	r.Equal("github-org:acme", ScopeOf(report, types.VersionedResource{Parents: []types.ParentResource{
+		{Kind: types.KindGithubRepo, ID: "acme/repo"},
+		{Kind: types.KindGithubOrg, ID: "acme"},
+	}}))
+}